@@ -0,0 +1,137 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	AdvancedSecurityApiVersion = "7.2-preview.1"
+)
+
+type AdvancedSecurityEnablement struct {
+	ProjectId     string `json:"projectId"`
+	RepositoryId  string `json:"repositoryId"`
+	AdvSecEnabled bool   `json:"advSecEnabled"`
+}
+
+type AdvancedSecurityAlertList struct {
+	Count int                     `json:"count"`
+	List  []AdvancedSecurityAlert `json:"value"`
+}
+
+type AdvancedSecurityAlert struct {
+	AlertId   int64  `json:"alertId"`
+	AlertType string `json:"alertType"`
+	Severity  string `json:"severity"`
+	State     string `json:"state"`
+	Title     string `json:"title"`
+
+	Rule struct {
+		Id           string `json:"id"`
+		Name         string `json:"name"`
+		FriendlyName string `json:"friendlyName"`
+	} `json:"rule"`
+
+	FirstSeenDate *time.Time `json:"firstSeenDate"`
+	LastSeenDate  *time.Time `json:"lastSeenDate"`
+	FixedDate     *time.Time `json:"fixedDate"`
+}
+
+// IsOpen returns true if the alert is active (not fixed or dismissed)
+func (a *AdvancedSecurityAlert) IsOpen() bool {
+	return a.State == "active"
+}
+
+// FixDuration returns the time from first detection until the alert was fixed
+func (a *AdvancedSecurityAlert) FixDuration() *time.Duration {
+	if a.State != "fixed" || a.FirstSeenDate == nil || a.FixedDate == nil {
+		return nil
+	}
+
+	ret := a.FixedDate.Sub(*a.FirstSeenDate)
+	return &ret
+}
+
+// RuleName returns the friendly name of the rule (or the rule name if not set)
+func (a *AdvancedSecurityAlert) RuleName() string {
+	if a.Rule.FriendlyName != "" {
+		return a.Rule.FriendlyName
+	}
+	return a.Rule.Name
+}
+
+func (c *AzureDevopsClient) GetAdvancedSecurityEnablement(project string, repository string) (enablement AdvancedSecurityEnablement, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/management/repositories/%v/enablement?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repository),
+		url.QueryEscape(c.apiVersion(AdvancedSecurityApiVersion)),
+	)
+	response, err := c.restAdvsec().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &enablement)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// ListAdvancedSecurityAlerts returns all alerts (code scanning, secret scanning and dependency scanning) of a repository
+func (c *AzureDevopsClient) ListAdvancedSecurityAlerts(project string, repository string) (list AdvancedSecurityAlertList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/alert/repositories/%v/alerts?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repository),
+		url.QueryEscape(c.apiVersion(AdvancedSecurityApiVersion)),
+	)
+	response, err := c.restAdvsec().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		response, err = c.restAdvsec().R().SetQueryParam("continuationToken", continuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList AdvancedSecurityAlertList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
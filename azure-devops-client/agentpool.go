@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -106,6 +109,7 @@ type AgentPoolAgent struct {
 	Name               string
 	OsDescription      string
 	SystemCapabilities map[string]string
+	UserCapabilities   map[string]string
 	ProvisioningState  string
 	Status             string
 	Version            string
@@ -113,13 +117,109 @@ type AgentPoolAgent struct {
 	AssignedRequest    JobRequest
 }
 
+type AgentCapability struct {
+	Name   string
+	Value  string
+	Type   string
+	Source string
+}
+
+type AgentPackageList struct {
+	Count int            `json:"count"`
+	List  []AgentPackage `json:"value"`
+}
+
+type AgentPackage struct {
+	Type      string              `json:"type"`
+	Platform  string              `json:"platform"`
+	CreatedOn time.Time           `json:"createdOn"`
+	Version   AgentPackageVersion `json:"version"`
+}
+
+type AgentPackageVersion struct {
+	Major int64 `json:"major"`
+	Minor int64 `json:"minor"`
+	Patch int64 `json:"patch"`
+}
+
+// ParseCapabilities returns the system and user capabilities of the agent matching the capability schema
+// (format 'capabilityName:type'), user capabilities take precedence over system capabilities
+func (a *AgentPoolAgent) ParseCapabilities(capabilitySchema []string) (capabilities []AgentCapability, error error) {
+	for _, cs := range capabilitySchema {
+		name, _type, err := extractTagSchema(cs)
+		if err != nil {
+			error = err
+			return
+		}
+
+		if value, exists := a.UserCapabilities[name]; exists {
+			capabilities = append(capabilities, AgentCapability{Name: name, Value: value, Type: _type, Source: "user"})
+		} else if value, exists := a.SystemCapabilities[name]; exists {
+			capabilities = append(capabilities, AgentCapability{Name: name, Value: value, Type: _type, Source: "system"})
+		}
+	}
+	return
+}
+
+var agentCapabilityNumberRegexp = regexp.MustCompile(`^v?([0-9]+(\.[0-9]+)?)`)
+
+// NumberValue returns the leading numeric part of the capability value (eg. major.minor of versions)
+func (c *AgentCapability) NumberValue() *float64 {
+	if match := agentCapabilityNumberRegexp.FindStringSubmatch(strings.TrimSpace(c.Value)); match != nil {
+		if value, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return &value
+		}
+	}
+	return nil
+}
+
+// ParseAgentPackageVersion parses agent versions in the format 'major.minor.patch'
+func ParseAgentPackageVersion(version string) (ret AgentPackageVersion) {
+	parts := strings.SplitN(version, ".", 3)
+	values := []*int64{&ret.Major, &ret.Minor, &ret.Patch}
+	for i, part := range parts {
+		if val, err := strconv.ParseInt(part, 10, 64); err == nil {
+			*values[i] = val
+		}
+	}
+	return
+}
+
+func (v AgentPackageVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func (v AgentPackageVersion) Less(other AgentPackageVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// LatestVersion returns the highest agent version of all platforms
+func (l *AgentPackageList) LatestVersion() *AgentPackageVersion {
+	var ret *AgentPackageVersion
+	for _, agentPackage := range l.List {
+		if ret == nil || ret.Less(agentPackage.Version) {
+			version := agentPackage.Version
+			ret = &version
+		}
+	}
+	return ret
+}
+
 type JobRequest struct {
 	RequestId    int64
 	Demands      []string
 	QueueTime    time.Time
 	AssignTime   *time.Time
 	ReceiveTime  time.Time
+	FinishTime   *time.Time
 	LockedUntil  time.Time
+	Result       string
 	ServiceOwner string
 	HostId       string
 	ScopeId      string
@@ -131,6 +231,31 @@ type JobRequest struct {
 		Name  string
 		Links Links `json:"_links"`
 	}
+	Owner struct {
+		Id    int64
+		Name  string
+		Links Links `json:"_links"`
+	}
+}
+
+// WaitDuration returns the time the job was waiting in the queue (queued till assigned)
+func (j *JobRequest) WaitDuration() *time.Duration {
+	if j.AssignTime == nil || j.QueueTime.IsZero() {
+		return nil
+	}
+
+	ret := j.AssignTime.Sub(j.QueueTime)
+	return &ret
+}
+
+// ExecutionDuration returns the execution time of the job (assigned till finished)
+func (j *JobRequest) ExecutionDuration() *time.Duration {
+	if j.AssignTime == nil || j.FinishTime == nil {
+		return nil
+	}
+
+	ret := j.FinishTime.Sub(*j.AssignTime)
+	return &ret
 }
 
 func (c *AzureDevopsClient) ListAgentPools() (list AgentPoolList, error error) {
@@ -206,3 +331,26 @@ func (c *AzureDevopsClient) ListAgentPoolJobs(agentPoolId int64) (list AgentPool
 
 	return
 }
+
+func (c *AzureDevopsClient) ListAgentPackages() (list AgentPackageList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"/_apis/distributedtask/packages/agent?api-version=%s",
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
@@ -0,0 +1,89 @@
+package AzureDevopsClient
+
+import (
+	"testing"
+)
+
+func TestParseAgentPackageVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected AgentPackageVersion
+	}{
+		{version: "3.236.1", expected: AgentPackageVersion{Major: 3, Minor: 236, Patch: 1}},
+		{version: "4.248.0", expected: AgentPackageVersion{Major: 4, Minor: 248, Patch: 0}},
+		{version: "3.236", expected: AgentPackageVersion{Major: 3, Minor: 236, Patch: 0}},
+		{version: "3", expected: AgentPackageVersion{Major: 3, Minor: 0, Patch: 0}},
+		{version: "3.x.1", expected: AgentPackageVersion{Major: 3, Minor: 0, Patch: 1}},
+		{version: "", expected: AgentPackageVersion{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			if actual := ParseAgentPackageVersion(test.version); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAgentPackageVersionLess(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{a: "3.236.1", b: "3.236.1", expected: false},
+		{a: "3.236.0", b: "3.236.1", expected: true},
+		{a: "3.236.1", b: "3.236.0", expected: false},
+		{a: "3.236.9", b: "3.240.0", expected: true},
+		{a: "3.240.0", b: "3.236.9", expected: false},
+		{a: "3.999.9", b: "4.0.0", expected: true},
+		{a: "4.0.0", b: "3.999.9", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+"<"+test.b, func(t *testing.T) {
+			if actual := ParseAgentPackageVersion(test.a).Less(ParseAgentPackageVersion(test.b)); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAgentPackageListLatestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected *AgentPackageVersion
+	}{
+		{
+			name:     "empty",
+			versions: []string{},
+			expected: nil,
+		},
+		{
+			name:     "platforms",
+			versions: []string{"3.236.1", "4.248.0", "3.240.0"},
+			expected: &AgentPackageVersion{Major: 4, Minor: 248, Patch: 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list := AgentPackageList{}
+			for _, version := range test.versions {
+				list.List = append(list.List, AgentPackage{Version: ParseAgentPackageVersion(version)})
+			}
+
+			actual := list.LatestVersion()
+			switch {
+			case test.expected == nil && actual != nil:
+				t.Errorf("expected nil, got %v", *actual)
+			case test.expected != nil && actual == nil:
+				t.Errorf("expected %v, got nil", *test.expected)
+			case test.expected != nil && *actual != *test.expected:
+				t.Errorf("expected %v, got %v", *test.expected, *actual)
+			}
+		})
+	}
+}
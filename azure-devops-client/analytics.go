@@ -0,0 +1,116 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type AnalyticsResultList struct {
+	List     []AnalyticsResultRow `json:"value"`
+	NextLink string               `json:"@odata.nextLink"`
+}
+
+type AnalyticsResultRow map[string]interface{}
+
+// Get returns the value of a (nested) result column, path segments are separated by "/" (eg. Pipeline/PipelineName)
+func (r AnalyticsResultRow) Get(path string) (ret interface{}) {
+	ret = map[string]interface{}(r)
+	for _, segment := range strings.Split(path, "/") {
+		if val, ok := ret.(map[string]interface{}); ok {
+			ret = val[segment]
+		} else {
+			return nil
+		}
+	}
+	return
+}
+
+func (r AnalyticsResultRow) GetString(path string) string {
+	switch val := r.Get(path).(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func (r AnalyticsResultRow) GetFloat64(path string) *float64 {
+	switch val := r.Get(path).(type) {
+	case float64:
+		return &val
+	case bool:
+		ret := float64(0)
+		if val {
+			ret = 1
+		}
+		return &ret
+	case string:
+		if ret, err := strconv.ParseFloat(val, 64); err == nil {
+			return &ret
+		}
+	}
+	return nil
+}
+
+func (c *AzureDevopsClient) QueryAnalytics(project string, query string) (list AnalyticsResultList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	entitySet, queryString, _ := strings.Cut(query, "?")
+
+	requestUrl := fmt.Sprintf(
+		"_odata/v4.0-preview/%v",
+		strings.TrimLeft(entitySet, "/"),
+	)
+	if project != "" {
+		requestUrl = fmt.Sprintf(
+			"%v/%v",
+			url.PathEscape(project),
+			requestUrl,
+		)
+	}
+
+	response, err := c.restAnalytics().R().SetQueryString(queryString).Get(requestUrl)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	// follow server side paging
+	nextLink := list.NextLink
+	for nextLink != "" {
+		response, err = c.restAnalytics().R().Get(nextLink)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList AnalyticsResultList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.List = append(list.List, tmpList.List...)
+		nextLink = tmpList.NextLink
+	}
+	list.NextLink = ""
+
+	return
+}
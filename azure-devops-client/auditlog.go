@@ -0,0 +1,93 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	AuditLogApiVersion = "7.1-preview.1"
+	AuditLogBatchSize  = 1000
+)
+
+type AuditLogList struct {
+	List              []AuditLogEntry `json:"decoratedAuditLogEntries"`
+	ContinuationToken string          `json:"continuationToken"`
+	HasMore           bool            `json:"hasMore"`
+}
+
+type AuditLogEntry struct {
+	Id            string    `json:"id"`
+	CorrelationId string    `json:"correlationId"`
+	ActivityId    string    `json:"activityId"`
+	ActionId      string    `json:"actionId"`
+	Area          string    `json:"area"`
+	Category      string    `json:"category"`
+	ScopeType     string    `json:"scopeType"`
+	ScopeId       string    `json:"scopeId"`
+	ProjectId     string    `json:"projectId"`
+	ProjectName   string    `json:"projectName"`
+	IpAddress     string    `json:"ipAddress"`
+	Details       string    `json:"details"`
+	Timestamp     time.Time `json:"timestamp"`
+
+	ActorDisplayName string `json:"actorDisplayName"`
+	ActorUPN         string `json:"actorUPN"`
+}
+
+// Actor returns the principal name of the actor (display name for service identities without UPN)
+func (e *AuditLogEntry) Actor() string {
+	if e.ActorUPN != "" {
+		return e.ActorUPN
+	}
+	return e.ActorDisplayName
+}
+
+// ListAuditLog returns the audit log entries (newest first) between startTime and endTime (requires View audit log permission)
+func (c *AzureDevopsClient) ListAuditLog(startTime, endTime time.Time) (list AuditLogList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/audit/auditlog?startTime=%v&endTime=%v&batchSize=%v&api-version=%v",
+		url.QueryEscape(startTime.UTC().Format(time.RFC3339Nano)),
+		url.QueryEscape(endTime.UTC().Format(time.RFC3339Nano)),
+		url.QueryEscape(int64ToString(AuditLogBatchSize)),
+		url.QueryEscape(c.apiVersion(AuditLogApiVersion)),
+	)
+	response, err := c.restAuditservice().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	for list.HasMore && list.ContinuationToken != "" {
+		// continuation tokens of the audit log contain reserved characters, let resty encode them
+		response, err = c.restAuditservice().R().SetQueryParam("continuationToken", list.ContinuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList AuditLogList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.List = append(list.List, tmpList.List...)
+		list.ContinuationToken = tmpList.ContinuationToken
+		list.HasMore = tmpList.HasMore
+	}
+
+	return
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
+const BuildRetentionApiVersion = "7.1-preview.1"
+
 type BuildDefinitionList struct {
 	Count int               `json:"count"`
 	List  []BuildDefinition `json:"value"`
@@ -56,6 +59,48 @@ type TimelineRecord struct {
 	State        string  `json:"state"`
 	StartTime    time.Time
 	FinishTime   time.Time
+
+	Task *struct {
+		Id      string `json:"id"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"task"`
+
+	Log *struct {
+		Id  int64  `json:"id"`
+		Url string `json:"url"`
+	} `json:"log"`
+
+	Issues []TimelineRecordIssue `json:"issues"`
+
+	Attempt          int64 `json:"attempt"`
+	PreviousAttempts []struct {
+		Attempt    int64  `json:"attempt"`
+		TimelineId string `json:"timelineId"`
+		RecordId   string `json:"recordId"`
+	} `json:"previousAttempts"`
+}
+
+type TimelineRecordIssue struct {
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+// Retries returns the number of retries (attempts after the first one) of the record
+func (r *TimelineRecord) Retries() int64 {
+	if r.Attempt > 1 {
+		return r.Attempt - 1
+	}
+	return int64(len(r.PreviousAttempts))
+}
+
+// TaskName returns the name of the task (eg. Bash, DotNetCoreCLI) or the record name if not available
+func (r *TimelineRecord) TaskName() string {
+	if r.Task != nil && r.Task.Name != "" {
+		return r.Task.Name
+	}
+	return r.Name
 }
 
 type Build struct {
@@ -85,9 +130,80 @@ type Build struct {
 	RequestedBy  IdentifyRef
 	RequestedFor IdentifyRef
 
+	KeepForever       bool `json:"keepForever"`
+	RetainedByRelease bool `json:"retainedByRelease"`
+
 	Links Links `json:"_links"`
 }
 
+type BuildArtifactList struct {
+	Count int             `json:"count"`
+	List  []BuildArtifact `json:"value"`
+}
+
+type BuildArtifact struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Resource struct {
+		Type       string            `json:"type"`
+		Properties map[string]string `json:"properties"`
+	} `json:"resource"`
+}
+
+type BuildRetentionLeaseList struct {
+	Count int                   `json:"count"`
+	List  []BuildRetentionLease `json:"value"`
+}
+
+type BuildRetentionLease struct {
+	LeaseId         int64     `json:"leaseId"`
+	OwnerId         string    `json:"ownerId"`
+	RunId           int64     `json:"runId"`
+	DefinitionId    int64     `json:"definitionId"`
+	CreatedOn       time.Time `json:"createdOn"`
+	ValidUntil      time.Time `json:"validUntil"`
+	ProtectPipeline bool      `json:"protectPipeline"`
+}
+
+type BuildChangeList struct {
+	Count int           `json:"count"`
+	List  []BuildChange `json:"value"`
+}
+
+type BuildChange struct {
+	Id        string      `json:"id"`
+	Message   string      `json:"message"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Author    IdentifyRef `json:"author"`
+}
+
+// Oldest returns the timestamp of the oldest change (commit) of the build
+func (l *BuildChangeList) Oldest() *time.Time {
+	var ret *time.Time
+	for i, change := range l.List {
+		if change.Timestamp.IsZero() {
+			continue
+		}
+
+		if ret == nil || change.Timestamp.Before(*ret) {
+			ret = &l.List[i].Timestamp
+		}
+	}
+	return ret
+}
+
+// Size returns the artifact size in bytes (if reported by the artifact resource)
+func (a *BuildArtifact) Size() *float64 {
+	if val, exists := a.Resource.Properties["artifactsize"]; exists {
+		if size, err := strconv.ParseFloat(val, 64); err == nil {
+			return &size
+		}
+	}
+	return nil
+}
+
 func (b *Build) QueueDuration() time.Duration {
 	return b.StartTime.Sub(b.QueueTime)
 }
@@ -266,6 +382,40 @@ func (c *AzureDevopsClient) ListBuildTimeline(project string, buildID string) (l
 	return
 }
 
+// GetBuildLogLines returns the lines of the build log (without leading timestamps)
+func (c *AzureDevopsClient) GetBuildLogLines(project string, buildID string, logID int64) (lines []string, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/build/builds/%v/logs/%v?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(buildID),
+		url.QueryEscape(int64ToString(logID)),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().SetHeader("Accept", "text/plain").Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	for _, line := range strings.Split(string(response.Body()), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// strip timestamp prefix (eg. 2024-01-01T12:00:00.0000000Z)
+		if timestamp, message, found := strings.Cut(line, " "); found {
+			if _, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+				line = message
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return
+}
+
 func (c *AzureDevopsClient) ListBuildTags(project string, buildID string) (list TagList, error error) {
 	defer c.concurrencyUnlock()
 	c.concurrencyLock()
@@ -290,6 +440,81 @@ func (c *AzureDevopsClient) ListBuildTags(project string, buildID string) (list
 	return
 }
 
+func (c *AzureDevopsClient) ListBuildArtifacts(project string, buildID string) (list BuildArtifactList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/build/builds/%v/artifacts?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(buildID),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListBuildChanges(project string, buildID string) (list BuildChangeList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/build/builds/%v/changes?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(buildID),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListBuildRetentionLeases(project string, buildID string) (list BuildRetentionLeaseList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/build/builds/%v/leases?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(buildID),
+		url.QueryEscape(c.apiVersion(BuildRetentionApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
 func extractTagKeyValue(tag string) (k string, v string, error error) {
 	parts := strings.Split(tag, "=")
 	if len(parts) != 2 {
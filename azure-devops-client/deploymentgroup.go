@@ -0,0 +1,124 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type DeploymentGroupList struct {
+	Count int               `json:"count"`
+	List  []DeploymentGroup `json:"value"`
+}
+
+type DeploymentGroup struct {
+	Id           int64  `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	MachineCount int64  `json:"machineCount"`
+
+	Pool struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"pool"`
+}
+
+type DeploymentTargetList struct {
+	Count int                `json:"count"`
+	List  []DeploymentTarget `json:"value"`
+}
+
+type DeploymentTarget struct {
+	Id    int64                 `json:"id"`
+	Tags  []string              `json:"tags"`
+	Agent DeploymentTargetAgent `json:"agent"`
+}
+
+type DeploymentTargetAgent struct {
+	Id                   int64       `json:"id"`
+	Name                 string      `json:"name"`
+	Version              string      `json:"version"`
+	OsDescription        string      `json:"osDescription"`
+	Enabled              bool        `json:"enabled"`
+	Status               string      `json:"status"`
+	CreatedOn            time.Time   `json:"createdOn"`
+	LastCompletedRequest *JobRequest `json:"lastCompletedRequest"`
+}
+
+func (c *AzureDevopsClient) ListDeploymentGroups(project string) (list DeploymentGroupList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/deploymentgroups?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListDeploymentTargets(project string, deploymentGroupId int64) (list DeploymentTargetList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/deploymentgroups/%v/targets?api-version=%v&$expand=lastCompletedRequest",
+		url.QueryEscape(project),
+		fmt.Sprintf("%d", deploymentGroupId),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		continuationUrl := fmt.Sprintf(
+			"%v&continuationToken=%v",
+			url,
+			continuationToken,
+		)
+
+		response, err = c.rest().R().Get(continuationUrl)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList DeploymentTargetList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const ElasticPoolApiVersion = "7.1-preview.1"
+
+type ElasticPoolList struct {
+	Count int           `json:"count"`
+	List  []ElasticPool `json:"value"`
+}
+
+type ElasticPool struct {
+	PoolId              int64      `json:"poolId"`
+	ServiceEndpointId   string     `json:"serviceEndpointId"`
+	AzureId             string     `json:"azureId"`
+	OsType              string     `json:"osType"`
+	State               string     `json:"state"`
+	OfflineSince        *time.Time `json:"offlineSince"`
+	MaxCapacity         int64      `json:"maxCapacity"`
+	DesiredIdle         int64      `json:"desiredIdle"`
+	DesiredSize         int64      `json:"desiredSize"`
+	MaxSavedNodeCount   int64      `json:"maxSavedNodeCount"`
+	TimeToLiveMinutes   int64      `json:"timeToLiveMinutes"`
+	RecycleAfterEachUse bool       `json:"recycleAfterEachUse"`
+	AgentInteractiveUI  bool       `json:"agentInteractiveUI"`
+	SizingAttempts      int64      `json:"sizingAttempts"`
+}
+
+type ElasticNodeList struct {
+	Count int           `json:"count"`
+	List  []ElasticNode `json:"value"`
+}
+
+type ElasticNode struct {
+	Id             int64     `json:"id"`
+	Name           string    `json:"name"`
+	AgentId        int64     `json:"agentId"`
+	State          string    `json:"state"`
+	AgentState     string    `json:"agentState"`
+	ComputeState   string    `json:"computeState"`
+	DesiredState   string    `json:"desiredState"`
+	StateChangedOn time.Time `json:"stateChangedOn"`
+}
+
+type ElasticPoolLogList struct {
+	Count int              `json:"count"`
+	List  []ElasticPoolLog `json:"value"`
+}
+
+type ElasticPoolLog struct {
+	Id        int64     `json:"id"`
+	Level     string    `json:"level"`
+	Operation string    `json:"operation"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (c *AzureDevopsClient) ListElasticPools() (list ElasticPoolList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"/_apis/distributedtask/elasticpools?api-version=%v",
+		url.QueryEscape(c.apiVersion(ElasticPoolApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListElasticPoolNodes(poolId int64) (list ElasticNodeList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"/_apis/distributedtask/elasticpools/%v/nodes?api-version=%v",
+		fmt.Sprintf("%d", poolId),
+		url.QueryEscape(c.apiVersion(ElasticPoolApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListElasticPoolLogs(poolId int64, top int64) (list ElasticPoolLogList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"/_apis/distributedtask/elasticpools/%v/logs?$top=%v&api-version=%v",
+		fmt.Sprintf("%d", poolId),
+		fmt.Sprintf("%d", top),
+		url.QueryEscape(c.apiVersion(ElasticPoolApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
@@ -0,0 +1,62 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type EntraIdApplicationList struct {
+	List []EntraIdApplication `json:"value"`
+}
+
+type EntraIdApplication struct {
+	Id                  string                         `json:"id"`
+	AppId               string                         `json:"appId"`
+	DisplayName         string                         `json:"displayName"`
+	PasswordCredentials []EntraIdApplicationCredential `json:"passwordCredentials"`
+	KeyCredentials      []EntraIdApplicationCredential `json:"keyCredentials"`
+}
+
+type EntraIdApplicationCredential struct {
+	KeyId         string     `json:"keyId"`
+	DisplayName   string     `json:"displayName"`
+	StartDateTime *time.Time `json:"startDateTime"`
+	EndDateTime   *time.Time `json:"endDateTime"`
+}
+
+// GetEntraIdApplication returns the application registration (with secrets and certificates) by its application (client) id
+func (c *AzureDevopsClient) GetEntraIdApplication(appId string) (application *EntraIdApplication, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	request, err := c.restGraph()
+	if err != nil {
+		error = err
+		return
+	}
+
+	url := fmt.Sprintf(
+		"applications?$filter=%v&$select=id,appId,displayName,passwordCredentials,keyCredentials",
+		url.QueryEscape(fmt.Sprintf("appId eq '%v'", appId)),
+	)
+	response, err := request.Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	var list EntraIdApplicationList
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	if len(list.List) >= 1 {
+		application = &list.List[0]
+	}
+
+	return
+}
@@ -0,0 +1,222 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	FeedApiVersion               = "7.1"
+	FeedPackageApiVersion        = "7.1"
+	FeedPackageMetricsApiVersion = "7.1-preview.1"
+
+	// number of packages per download statistics request
+	FeedPackageMetricsBatchSize = 100
+)
+
+type FeedList struct {
+	Count int    `json:"count"`
+	List  []Feed `json:"value"`
+}
+
+type Feed struct {
+	Id              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Url             string `json:"url"`
+	UpstreamEnabled bool   `json:"upstreamEnabled"`
+	Capabilities    string `json:"capabilities"`
+
+	HideDeletedPackageVersions bool `json:"hideDeletedPackageVersions"`
+
+	Project *struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+
+	UpstreamSources []FeedUpstreamSource `json:"upstreamSources"`
+}
+
+type FeedUpstreamSource struct {
+	Id                 string `json:"id"`
+	Name               string `json:"name"`
+	Protocol           string `json:"protocol"`
+	Location           string `json:"location"`
+	UpstreamSourceType string `json:"upstreamSourceType"`
+	Status             string `json:"status"`
+}
+
+type FeedPackageList struct {
+	Count int           `json:"count"`
+	List  []FeedPackage `json:"value"`
+}
+
+type FeedPackage struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	NormalizedName string `json:"normalizedName"`
+	ProtocolType   string `json:"protocolType"`
+
+	Versions []FeedPackageVersion `json:"versions"`
+}
+
+type FeedPackageVersion struct {
+	Id          string     `json:"id"`
+	Version     string     `json:"version"`
+	IsLatest    bool       `json:"isLatest"`
+	IsListed    bool       `json:"isListed"`
+	IsDeleted   bool       `json:"isDeleted"`
+	PublishDate *time.Time `json:"publishDate"`
+}
+
+type FeedPackageMetricsList struct {
+	Count int                  `json:"count"`
+	List  []FeedPackageMetrics `json:"value"`
+}
+
+type FeedPackageMetrics struct {
+	PackageId           string     `json:"packageId"`
+	DownloadCount       float64    `json:"downloadCount"`
+	DownloadUniqueUsers float64    `json:"downloadUniqueUsers"`
+	LastDownloaded      *time.Time `json:"lastDownloaded"`
+}
+
+// ProjectId returns the id of the project the feed is scoped to (empty for organization scoped feeds)
+func (f *Feed) ProjectId() string {
+	if f.Project != nil {
+		return f.Project.Id
+	}
+	return ""
+}
+
+// LatestVersion returns the latest version of the package
+func (p *FeedPackage) LatestVersion() *FeedPackageVersion {
+	for i, version := range p.Versions {
+		if version.IsLatest {
+			return &p.Versions[i]
+		}
+	}
+	return nil
+}
+
+// feedUrlPrefix returns the url prefix for project scoped (project not empty) or organization scoped feeds
+func feedUrlPrefix(project string) string {
+	if project != "" {
+		return url.QueryEscape(project) + "/"
+	}
+	return ""
+}
+
+// ListFeeds returns the feeds of a project (or organization scoped feeds if project is empty)
+func (c *AzureDevopsClient) ListFeeds(project string) (list FeedList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v_apis/packaging/feeds?api-version=%v",
+		feedUrlPrefix(project),
+		url.QueryEscape(c.apiVersion(FeedApiVersion)),
+	)
+	response, err := c.restFeeds().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// ListFeedPackages returns all packages (including all versions) of a feed
+func (c *AzureDevopsClient) ListFeedPackages(project string, feedId string) (list FeedPackageList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	// packages are paged using $top and $skip (max 1000 per request)
+	top := int64(1000)
+	for skip := int64(0); ; skip += top {
+		url := fmt.Sprintf(
+			"%v_apis/packaging/feeds/%v/packages?includeAllVersions=true&$top=%v&$skip=%v&api-version=%v",
+			feedUrlPrefix(project),
+			url.QueryEscape(feedId),
+			url.QueryEscape(int64ToString(top)),
+			url.QueryEscape(int64ToString(skip)),
+			url.QueryEscape(c.apiVersion(FeedPackageApiVersion)),
+		)
+		response, err := c.restFeeds().R().Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList FeedPackageList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		if int64(len(tmpList.List)) < top {
+			break
+		}
+	}
+
+	return
+}
+
+// ListFeedPackageMetrics returns the download statistics of packages
+func (c *AzureDevopsClient) ListFeedPackageMetrics(project string, feedId string, packageIds []string) (list FeedPackageMetricsList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v_apis/packaging/feeds/%v/packagemetricsbatch?api-version=%v",
+		feedUrlPrefix(project),
+		url.QueryEscape(feedId),
+		url.QueryEscape(c.apiVersion(FeedPackageMetricsApiVersion)),
+	)
+
+	for start := 0; start < len(packageIds); start += FeedPackageMetricsBatchSize {
+		end := start + FeedPackageMetricsBatchSize
+		if end > len(packageIds) {
+			end = len(packageIds)
+		}
+
+		payload := struct {
+			PackageIds []string `json:"packageIds"`
+		}{
+			PackageIds: packageIds[start:end],
+		}
+
+		req := c.restFeeds().NewRequest()
+		req.SetHeader("Content-Type", "application/json")
+		req.SetBody(payload)
+		response, err := req.Post(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList FeedPackageMetricsList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+	}
+
+	return
+}
@@ -0,0 +1,174 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	IdentityApiVersion   = "7.1-preview.1"
+	TokenAdminApiVersion = "7.1-preview.1"
+)
+
+type ConnectionData struct {
+	AuthenticatedUser struct {
+		Id                  string `json:"id"`
+		Descriptor          string `json:"descriptor"`
+		SubjectDescriptor   string `json:"subjectDescriptor"`
+		ProviderDisplayName string `json:"providerDisplayName"`
+	} `json:"authenticatedUser"`
+}
+
+type GraphUserList struct {
+	Count int         `json:"count"`
+	List  []GraphUser `json:"value"`
+}
+
+type GraphUser struct {
+	Descriptor    string `json:"descriptor"`
+	DisplayName   string `json:"displayName"`
+	PrincipalName string `json:"principalName"`
+	MailAddress   string `json:"mailAddress"`
+	Origin        string `json:"origin"`
+	OriginId      string `json:"originId"`
+	SubjectKind   string `json:"subjectKind"`
+}
+
+type PersonalAccessTokenList struct {
+	ContinuationToken string                `json:"continuationToken"`
+	List              []PersonalAccessToken `json:"value"`
+}
+
+type PersonalAccessToken struct {
+	AuthorizationId string    `json:"authorizationId"`
+	DisplayName     string    `json:"displayName"`
+	Scope           string    `json:"scope"`
+	TargetAccounts  []string  `json:"targetAccounts"`
+	ValidFrom       time.Time `json:"validFrom"`
+	ValidTo         time.Time `json:"validTo"`
+	IsValid         bool      `json:"isValid"`
+	IsPublic        bool      `json:"isPublic"`
+}
+
+// GetConnectionData returns the connection data (eg. authenticated identity) of the exporter
+func (c *AzureDevopsClient) GetConnectionData() (data ConnectionData, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := "_apis/connectionData"
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &data)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListGraphUsers() (list GraphUserList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/graph/users?subjectTypes=aad,msa&api-version=%v",
+		url.QueryEscape(c.apiVersion(IdentityApiVersion)),
+	)
+	response, err := c.restVssps().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		continuationUrl := fmt.Sprintf(
+			"%v&continuationToken=%v",
+			url,
+			continuationToken,
+		)
+
+		response, err = c.restVssps().R().Get(continuationUrl)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList GraphUserList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
+
+// ListPersonalAccessTokens returns the personal access tokens of an user (requires token administration permissions)
+func (c *AzureDevopsClient) ListPersonalAccessTokens(subjectDescriptor string) (list PersonalAccessTokenList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/tokenadmin/personalaccesstokens/%v?isPublic=false&api-version=%v",
+		url.QueryEscape(subjectDescriptor),
+		url.QueryEscape(c.apiVersion(TokenAdminApiVersion)),
+	)
+	response, err := c.restVssps().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	for list.ContinuationToken != "" {
+		continuationUrl := fmt.Sprintf(
+			"%v&continuationToken=%v",
+			url,
+			list.ContinuationToken,
+		)
+
+		response, err = c.restVssps().R().Get(continuationUrl)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList PersonalAccessTokenList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.List = append(list.List, tmpList.List...)
+		list.ContinuationToken = tmpList.ContinuationToken
+	}
+
+	return
+}
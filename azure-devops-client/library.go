@@ -0,0 +1,126 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	VariableGroupApiVersion = "7.1"
+	SecureFileApiVersion    = "7.1-preview.1"
+)
+
+type VariableGroupList struct {
+	Count int             `json:"count"`
+	List  []VariableGroup `json:"value"`
+}
+
+type VariableGroup struct {
+	Id          int64  `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsShared    bool   `json:"isShared"`
+
+	Variables map[string]VariableGroupVariable `json:"variables"`
+
+	// only set for key vault linked groups
+	ProviderData *struct {
+		ServiceEndpointId string     `json:"serviceEndpointId"`
+		Vault             string     `json:"vault"`
+		LastRefreshedOn   *time.Time `json:"lastRefreshedOn"`
+	} `json:"providerData"`
+
+	CreatedBy  IdentifyRef `json:"createdBy"`
+	CreatedOn  time.Time   `json:"createdOn"`
+	ModifiedBy IdentifyRef `json:"modifiedBy"`
+	ModifiedOn time.Time   `json:"modifiedOn"`
+}
+
+type VariableGroupVariable struct {
+	IsSecret bool `json:"isSecret"`
+
+	// key vault secrets
+	Enabled *bool      `json:"enabled"`
+	Expires *time.Time `json:"expires"`
+}
+
+type SecureFileList struct {
+	Count int          `json:"count"`
+	List  []SecureFile `json:"value"`
+}
+
+type SecureFile struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+
+	CreatedBy  IdentifyRef `json:"createdBy"`
+	CreatedOn  time.Time   `json:"createdOn"`
+	ModifiedBy IdentifyRef `json:"modifiedBy"`
+	ModifiedOn time.Time   `json:"modifiedOn"`
+}
+
+// IsKeyVault returns true if the variable group is linked to an Azure Key Vault
+func (g *VariableGroup) IsKeyVault() bool {
+	return g.Type == "AzureKeyVault" && g.ProviderData != nil
+}
+
+// SecretCount returns the number of secret variables (all variables of key vault linked groups are secrets)
+func (g *VariableGroup) SecretCount() (count int64) {
+	for _, variable := range g.Variables {
+		if variable.IsSecret || g.IsKeyVault() {
+			count++
+		}
+	}
+	return
+}
+
+func (c *AzureDevopsClient) ListVariableGroups(project string) (list VariableGroupList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/variablegroups?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(VariableGroupApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListSecureFiles(project string) (list SecureFileList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/securefiles?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(SecureFileApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
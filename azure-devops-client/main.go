@@ -19,6 +19,9 @@ import (
 
 const (
 	AZURE_DEVOPS_SCOPE = "499b84ac-1321-427f-aa17-267ca6975798/.default"
+
+	MICROSOFT_GRAPH_SCOPE = "https://graph.microsoft.com/.default"
+	MICROSOFT_GRAPH_URL   = "https://graph.microsoft.com/v1.0/"
 )
 
 type AzureDevopsClient struct {
@@ -42,8 +45,16 @@ type AzureDevopsClient struct {
 
 	ApiVersion string
 
-	restClient     *resty.Client
-	restClientVsrm *resty.Client
+	restClient          *resty.Client
+	restClientVsrm      *resty.Client
+	restClientAnalytics *resty.Client
+	restClientVssps     *resty.Client
+	restClientGraph     *resty.Client
+
+	restClientAuditservice *resty.Client
+	restClientVsaex        *resty.Client
+	restClientFeeds        *resty.Client
+	restClientAdvsec       *resty.Client
 
 	semaphore   chan bool
 	concurrency int64
@@ -57,6 +68,7 @@ type AzureDevopsClient struct {
 	LimitDeploymentPerDefinition      int64
 	LimitReleaseDefinitionsPerProject int64
 	LimitReleasesPerProject           int64
+	LimitPullRequestsPerRepository    int64
 
 	prometheus struct {
 		apiRequest *prometheus.HistogramVec
@@ -97,6 +109,7 @@ func (c *AzureDevopsClient) Init() {
 	c.LimitDeploymentPerDefinition = 100
 	c.LimitReleaseDefinitionsPerProject = 100
 	c.LimitReleasesPerProject = 100
+	c.LimitPullRequestsPerRepository = 100
 
 	c.prometheus.apiRequest = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -125,17 +138,89 @@ func (c *AzureDevopsClient) SetRetries(v int) {
 	if c.restClientVsrm != nil {
 		c.restClientVsrm.SetRetryCount(c.RequestRetries)
 	}
+
+	if c.restClientAnalytics != nil {
+		c.restClientAnalytics.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientVssps != nil {
+		c.restClientVssps.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientGraph != nil {
+		c.restClientGraph.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientAuditservice != nil {
+		c.restClientAuditservice.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientVsaex != nil {
+		c.restClientVsaex.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientFeeds != nil {
+		c.restClientFeeds.SetRetryCount(c.RequestRetries)
+	}
+
+	if c.restClientAdvsec != nil {
+		c.restClientAdvsec.SetRetryCount(c.RequestRetries)
+	}
 }
 
 func (c *AzureDevopsClient) SetUserAgent(v string) {
 	c.rest().SetHeader("User-Agent", v)
 	c.restVsrm().SetHeader("User-Agent", v)
+	c.restAnalytics().SetHeader("User-Agent", v)
+	c.restVssps().SetHeader("User-Agent", v)
+	c.restAuditservice().SetHeader("User-Agent", v)
+	c.restVsaex().SetHeader("User-Agent", v)
+	c.restFeeds().SetHeader("User-Agent", v)
+	c.restAdvsec().SetHeader("User-Agent", v)
 }
 
 func (c *AzureDevopsClient) SetApiVersion(apiversion string) {
 	c.ApiVersion = apiversion
 }
 
+// apiVersion returns the configured api version or the minimum api version required by a resource
+// (if the configured api version is older), preview resources are pinned to their own version
+// as the preview revision differs between api versions
+func (c *AzureDevopsClient) apiVersion(minimum string) string {
+	if strings.Contains(minimum, "-") {
+		return minimum
+	}
+
+	configured, _, _ := strings.Cut(c.ApiVersion, "-")
+	if compareApiVersion(configured, minimum) < 0 {
+		return minimum
+	}
+
+	return c.ApiVersion
+}
+
+// compareApiVersion compares two api versions (eg. 5.1 and 7.1) numerically
+func compareApiVersion(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aVal, bVal := 0, 0
+		if i < len(aParts) {
+			aVal, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bVal, _ = strconv.Atoi(bParts[i])
+		}
+
+		if aVal != bVal {
+			return aVal - bVal
+		}
+	}
+
+	return 0
+}
+
 func (c *AzureDevopsClient) SetOrganization(url string) {
 	c.organization = &url
 }
@@ -152,9 +237,21 @@ func (c *AzureDevopsClient) UseAzAuth() error {
 	}
 
 	c.azcreds = cred
+
+	c.restClientGraph = resty.New()
+	c.restClientGraph.SetBaseURL(MICROSOFT_GRAPH_URL)
+	c.restClientGraph.SetHeader("Accept", "application/json")
+	c.restClientGraph.SetRetryCount(c.RequestRetries)
+	c.restClientGraph.OnBeforeRequest(c.restOnBeforeRequest)
+	c.restClientGraph.OnAfterResponse(c.restOnAfterResponse)
+
 	return nil
 }
 
+func (c *AzureDevopsClient) SupportsAzAuth() bool {
+	return c.azcreds != nil
+}
+
 func (c *AzureDevopsClient) SupportsPatAuthentication() bool {
 	return c.accessToken != nil && len(*c.accessToken) > 0
 }
@@ -179,6 +276,84 @@ func (c *AzureDevopsClient) restVsrm() *resty.Client {
 	return client
 }
 
+func (c *AzureDevopsClient) restAnalytics() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientAnalytics, "analytics.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+func (c *AzureDevopsClient) restVssps() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientVssps, "vssps.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+func (c *AzureDevopsClient) restAuditservice() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientAuditservice, "auditservice.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+func (c *AzureDevopsClient) restVsaex() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientVsaex, "vsaex.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+func (c *AzureDevopsClient) restFeeds() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientFeeds, "feeds.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+func (c *AzureDevopsClient) restAdvsec() *resty.Client {
+	var client, err = c.restWithAuthentication(c.restClientAdvsec, "advsec.dev.azure.com")
+
+	if err != nil {
+		c.logger.Fatalf("could not create a rest client: %v", err)
+	}
+
+	return client
+}
+
+// restGraph returns an authenticated request for Microsoft Graph, only available with azure authentication
+func (c *AzureDevopsClient) restGraph() (*resty.Request, error) {
+	if !c.SupportsAzAuth() {
+		return nil, errors.New("azure authentication is required for Microsoft Graph requests")
+	}
+
+	ctx := context.Background()
+	opts := policy.TokenRequestOptions{
+		Scopes: []string{MICROSOFT_GRAPH_SCOPE},
+	}
+	accessToken, err := c.azcreds.GetToken(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.restClientGraph.R().SetAuthToken(accessToken.Token), nil
+}
+
 func (c *AzureDevopsClient) restWithAuthentication(restClient *resty.Client, domain string) (*resty.Client, error) {
 	if restClient == nil {
 		restClient = c.restWithoutToken(domain)
@@ -0,0 +1,60 @@
+package AzureDevopsClient
+
+import (
+	"testing"
+)
+
+func TestCompareApiVersion(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "7.1", b: "7.1", expected: 0},
+		{a: "7.1", b: "7.2", expected: -1},
+		{a: "7.2", b: "7.1", expected: 1},
+		{a: "5.1", b: "7.1", expected: -1},
+		{a: "10.0", b: "9.1", expected: 1},
+		{a: "7", b: "7.0", expected: 0},
+		{a: "7", b: "7.1", expected: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+"/"+test.b, func(t *testing.T) {
+			actual := compareApiVersion(test.a, test.b)
+			switch {
+			case test.expected == 0 && actual != 0,
+				test.expected < 0 && actual >= 0,
+				test.expected > 0 && actual <= 0:
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestApiVersion(t *testing.T) {
+	tests := []struct {
+		configured string
+		minimum    string
+		expected   string
+	}{
+		{configured: "7.1", minimum: "7.1", expected: "7.1"},
+		{configured: "7.2", minimum: "7.1", expected: "7.2"},
+		{configured: "5.1", minimum: "7.1", expected: "7.1"},
+		{configured: "7.2-preview.1", minimum: "7.1", expected: "7.2-preview.1"},
+		{configured: "7.1-preview.1", minimum: "7.1", expected: "7.1-preview.1"},
+		{configured: "5.1", minimum: "7.1-preview.1", expected: "7.1-preview.1"},
+		{configured: "7.1", minimum: "7.1-preview.3", expected: "7.1-preview.3"},
+		{configured: "7.2", minimum: "7.1-preview.1", expected: "7.1-preview.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.configured+"/"+test.minimum, func(t *testing.T) {
+			c := AzureDevopsClient{}
+			c.SetApiVersion(test.configured)
+			if actual := c.apiVersion(test.minimum); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
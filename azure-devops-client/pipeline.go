@@ -0,0 +1,404 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const (
+	PipelineApiVersion           = "7.1"
+	EnvironmentApiVersion        = "7.1-preview.1"
+	PipelinePermissionApiVersion = "7.1-preview.1"
+)
+
+type PipelineList struct {
+	Count int        `json:"count"`
+	List  []Pipeline `json:"value"`
+}
+
+type Pipeline struct {
+	Id       int64  `json:"id"`
+	Revision int64  `json:"revision"`
+	Name     string `json:"name"`
+	Folder   string `json:"folder"`
+
+	Configuration struct {
+		Type       string `json:"type"`
+		Path       string `json:"path"`
+		Repository struct {
+			Id   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"repository"`
+	} `json:"configuration"`
+
+	Links Links `json:"_links"`
+}
+
+type PipelineRunList struct {
+	Count int           `json:"count"`
+	List  []PipelineRun `json:"value"`
+}
+
+type PipelineRun struct {
+	Id           int64      `json:"id"`
+	Name         string     `json:"name"`
+	State        string     `json:"state"`
+	Result       string     `json:"result"`
+	CreatedDate  time.Time  `json:"createdDate"`
+	FinishedDate *time.Time `json:"finishedDate"`
+
+	Pipeline struct {
+		Id     int64  `json:"id"`
+		Name   string `json:"name"`
+		Folder string `json:"folder"`
+	} `json:"pipeline"`
+
+	TemplateParameters map[string]interface{} `json:"templateParameters"`
+
+	Resources PipelineRunResources `json:"resources"`
+}
+
+type PipelineRunResources struct {
+	Repositories map[string]struct {
+		Repository struct {
+			Id       string `json:"id"`
+			Type     string `json:"type"`
+			FullName string `json:"fullName"`
+		} `json:"repository"`
+		RefName string `json:"refName"`
+		Version string `json:"version"`
+	} `json:"repositories"`
+
+	Pipelines map[string]struct {
+		Pipeline struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"pipeline"`
+		Version string `json:"version"`
+	} `json:"pipelines"`
+
+	Containers map[string]struct {
+		Container struct {
+			Image string `json:"image"`
+		} `json:"container"`
+	} `json:"containers"`
+}
+
+type PipelineRunResource struct {
+	Type    string
+	Alias   string
+	Name    string
+	Version string
+}
+
+type EnvironmentList struct {
+	Count int           `json:"count"`
+	List  []Environment `json:"value"`
+}
+
+type Environment struct {
+	Id             int64     `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	CreatedOn      time.Time `json:"createdOn"`
+	LastModifiedOn time.Time `json:"lastModifiedOn"`
+}
+
+type EnvironmentDeploymentRecordList struct {
+	Count int                           `json:"count"`
+	List  []EnvironmentDeploymentRecord `json:"value"`
+}
+
+type EnvironmentDeploymentRecord struct {
+	Id            int64      `json:"id"`
+	EnvironmentId int64      `json:"environmentId"`
+	StageName     string     `json:"stageName"`
+	JobName       string     `json:"jobName"`
+	Result        string     `json:"result"`
+	PlanType      string     `json:"planType"`
+	QueueTime     time.Time  `json:"queueTime"`
+	StartTime     *time.Time `json:"startTime"`
+	FinishTime    *time.Time `json:"finishTime"`
+
+	// pipeline
+	Definition struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"definition"`
+
+	// pipeline run
+	Owner struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"owner"`
+}
+
+type PipelinePermission struct {
+	Resource struct {
+		Id   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"resource"`
+
+	AllPipelines *PipelinePermissionAuthorization `json:"allPipelines"`
+
+	Pipelines []struct {
+		Id int64 `json:"id"`
+		PipelinePermissionAuthorization
+	} `json:"pipelines"`
+}
+
+type PipelinePermissionAuthorization struct {
+	Authorized   bool        `json:"authorized"`
+	AuthorizedBy IdentifyRef `json:"authorizedBy"`
+	AuthorizedOn *time.Time  `json:"authorizedOn"`
+}
+
+// List returns all resources (repositories, pipelines, containers) used by the run, sorted by type and alias
+func (r *PipelineRunResources) List() (list []PipelineRunResource) {
+	for alias, repository := range r.Repositories {
+		name := repository.Repository.FullName
+		if name == "" {
+			name = repository.Repository.Id
+		}
+		list = append(list, PipelineRunResource{Type: "repository", Alias: alias, Name: name, Version: repository.Version})
+	}
+
+	for alias, pipeline := range r.Pipelines {
+		list = append(list, PipelineRunResource{Type: "pipeline", Alias: alias, Name: pipeline.Pipeline.Name, Version: pipeline.Version})
+	}
+
+	for alias, container := range r.Containers {
+		list = append(list, PipelineRunResource{Type: "container", Alias: alias, Name: container.Container.Image})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return list[i].Alias < list[j].Alias
+	})
+
+	return
+}
+
+func (c *AzureDevopsClient) ListPipelines(project string) (list PipelineList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/pipelines?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(PipelineApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		continuationUrl := fmt.Sprintf(
+			"%v&continuationToken=%v",
+			url,
+			continuationToken,
+		)
+
+		response, err = c.rest().R().Get(continuationUrl)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList PipelineList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) GetPipeline(project string, pipelineId int64) (pipeline Pipeline, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/pipelines/%v?api-version=%v",
+		url.QueryEscape(project),
+		fmt.Sprintf("%d", pipelineId),
+		url.QueryEscape(c.apiVersion(PipelineApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &pipeline)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// ListPipelineRuns returns the latest runs (limited by LimitBuildsPerDefinition) of the pipeline created after minTime
+func (c *AzureDevopsClient) ListPipelineRuns(project string, pipelineId int64, minTime time.Time) (list PipelineRunList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/pipelines/%v/runs?api-version=%v",
+		url.QueryEscape(project),
+		fmt.Sprintf("%d", pipelineId),
+		url.QueryEscape(c.apiVersion(PipelineApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	var tmpList PipelineRunList
+	err = json.Unmarshal(response.Body(), &tmpList)
+	if err != nil {
+		error = err
+		return
+	}
+
+	// runs are returned newest first
+	for _, run := range tmpList.List {
+		if int64(len(list.List)) >= c.LimitBuildsPerDefinition {
+			break
+		}
+
+		if run.CreatedDate.After(minTime) {
+			list.List = append(list.List, run)
+		}
+	}
+	list.Count = len(list.List)
+
+	return
+}
+
+func (c *AzureDevopsClient) GetPipelineRun(project string, pipelineId, runId int64) (run PipelineRun, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/pipelines/%v/runs/%v?api-version=%v",
+		url.QueryEscape(project),
+		fmt.Sprintf("%d", pipelineId),
+		fmt.Sprintf("%d", runId),
+		url.QueryEscape(c.apiVersion(PipelineApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &run)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListEnvironments(project string) (list EnvironmentList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/environments?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(EnvironmentApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListEnvironmentDeploymentRecords(project string, environmentId int64) (list EnvironmentDeploymentRecordList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/distributedtask/environments/%v/environmentdeploymentrecords?top=%v&api-version=%v",
+		url.QueryEscape(project),
+		fmt.Sprintf("%d", environmentId),
+		url.QueryEscape(int64ToString(c.LimitBuildsPerProject)),
+		url.QueryEscape(c.apiVersion(EnvironmentApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// GetPipelinePermission returns the pipelines authorized to use a protected resource (endpoint, variablegroup, securefile, queue, environment)
+func (c *AzureDevopsClient) GetPipelinePermission(project string, resourceType string, resourceId string) (permission PipelinePermission, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/pipelines/pipelinePermissions/%v/%v?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(resourceType),
+		url.QueryEscape(resourceId),
+		url.QueryEscape(c.apiVersion(PipelinePermissionApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &permission)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
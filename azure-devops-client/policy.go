@@ -0,0 +1,192 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	PolicyTypeMinimumReviewers  = "minimumReviewers"
+	PolicyTypeRequiredReviewers = "requiredReviewers"
+	PolicyTypeBuildValidation   = "buildValidation"
+	PolicyTypeCommentResolution = "commentResolution"
+	PolicyTypeMergeStrategy     = "mergeStrategy"
+	PolicyTypeWorkItemLinking   = "workItemLinking"
+	PolicyTypeStatusCheck       = "statusCheck"
+)
+
+// well known policy type ids
+var policyTypeIdMap = map[string]string{
+	"fa4e907d-c16b-4a4c-9dfa-4906e5d171dd": PolicyTypeMinimumReviewers,
+	"fd2167ab-b0be-447a-8ec8-39368250530e": PolicyTypeRequiredReviewers,
+	"0609b952-1397-4640-95ec-e00a01b2c241": PolicyTypeBuildValidation,
+	"c6a1889d-b943-4856-b76f-9e46bb6b0df2": PolicyTypeCommentResolution,
+	"fa4e907d-c16b-4a4c-9dfa-4916e5d171ab": PolicyTypeMergeStrategy,
+	"40e92b44-2fe1-4dd6-b3d8-74a9c21d0c6e": PolicyTypeWorkItemLinking,
+	"cbdc66da-9728-4af8-aada-9a5a32e4a226": PolicyTypeStatusCheck,
+}
+
+type PolicyConfigurationList struct {
+	Count int                   `json:"count"`
+	List  []PolicyConfiguration `json:"value"`
+}
+
+type PolicyConfiguration struct {
+	Id         int64 `json:"id"`
+	Revision   int64 `json:"revision"`
+	IsEnabled  bool  `json:"isEnabled"`
+	IsBlocking bool  `json:"isBlocking"`
+	IsDeleted  bool  `json:"isDeleted"`
+
+	Type struct {
+		Id          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	} `json:"type"`
+
+	Settings PolicyConfigurationSettings `json:"settings"`
+}
+
+type PolicyConfigurationSettings struct {
+	Scope []PolicyConfigurationScope `json:"scope"`
+
+	// minimum reviewers
+	MinimumApproverCount *float64 `json:"minimumApproverCount"`
+	CreatorVoteCounts    *bool    `json:"creatorVoteCounts"`
+	ResetOnSourcePush    *bool    `json:"resetOnSourcePush"`
+
+	// build validation
+	BuildDefinitionId *int64   `json:"buildDefinitionId"`
+	ValidDuration     *float64 `json:"validDuration"`
+
+	// merge strategy
+	AllowNoFastForward *bool `json:"allowNoFastForward"`
+	AllowSquash        *bool `json:"allowSquash"`
+	AllowRebase        *bool `json:"allowRebase"`
+	AllowRebaseMerge   *bool `json:"allowRebaseMerge"`
+}
+
+type PolicyConfigurationScope struct {
+	RepositoryId *string `json:"repositoryId"`
+	RefName      *string `json:"refName"`
+	MatchKind    *string `json:"matchKind"`
+}
+
+// TypeKey returns the short name of well known policy types, otherwise the policy type display name
+func (p *PolicyConfiguration) TypeKey() string {
+	if val, exists := policyTypeIdMap[strings.ToLower(p.Type.Id)]; exists {
+		return val
+	}
+	return p.Type.DisplayName
+}
+
+// AppliesTo checks if the policy is scoped to the repository and branch (full ref name, eg. refs/heads/main)
+func (p *PolicyConfiguration) AppliesTo(repository Repository, refName string) bool {
+	for _, scope := range p.Settings.Scope {
+		if scope.Matches(repository, refName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PolicyConfigurationScope) Matches(repository Repository, refName string) bool {
+	if s.RepositoryId != nil && *s.RepositoryId != "" && !strings.EqualFold(*s.RepositoryId, repository.Id) {
+		return false
+	}
+
+	matchKind := ""
+	if s.MatchKind != nil {
+		matchKind = strings.ToLower(*s.MatchKind)
+	}
+
+	if matchKind == "defaultbranch" {
+		return refName == repository.DefaultBranch
+	}
+
+	if s.RefName == nil || *s.RefName == "" {
+		return true
+	}
+
+	switch matchKind {
+	case "prefix":
+		return strings.HasPrefix(refName, *s.RefName)
+	default:
+		return refName == *s.RefName
+	}
+}
+
+func (s *PolicyConfigurationScope) RepositoryIdString() string {
+	if s.RepositoryId != nil {
+		return *s.RepositoryId
+	}
+	return ""
+}
+
+// BranchNameString returns the scoped ref name without refs/heads/ prefix (same format as Repository.DefaultBranchName)
+func (s *PolicyConfigurationScope) BranchNameString() string {
+	if s.RefName != nil {
+		return strings.TrimPrefix(*s.RefName, "refs/heads/")
+	}
+	return ""
+}
+
+func (s *PolicyConfigurationScope) MatchKindString() string {
+	if s.MatchKind != nil {
+		return *s.MatchKind
+	}
+	return ""
+}
+
+func (c *AzureDevopsClient) ListPolicyConfigurations(project string) (list PolicyConfigurationList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/policy/configurations?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		continuationUrl := fmt.Sprintf(
+			"%v&continuationToken=%v",
+			url,
+			continuationToken,
+		)
+
+		response, err = c.rest().R().Get(continuationUrl)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList PolicyConfigurationList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 )
 
 type ProjectList struct {
@@ -21,6 +22,21 @@ type Project struct {
 	Revision    int64  `json:"revision"`
 	Visibility  string `json:"visibility"`
 
+	LastUpdateTime *time.Time `json:"lastUpdateTime"`
+
+	// only available via GetProject
+	DefaultTeam *struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"defaultTeam"`
+
+	Capabilities struct {
+		ProcessTemplate struct {
+			TemplateName   string `json:"templateName"`
+			TemplateTypeId string `json:"templateTypeId"`
+		} `json:"processTemplate"`
+	} `json:"capabilities"`
+
 	RepositoryList RepositoryList
 }
 
@@ -51,3 +67,28 @@ func (c *AzureDevopsClient) ListProjects() (list ProjectList, error error) {
 
 	return
 }
+
+// GetProject returns the project details including capabilities (process template) and the default team
+func (c *AzureDevopsClient) GetProject(project string) (details Project, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/projects/%v?includeCapabilities=true&api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &details)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
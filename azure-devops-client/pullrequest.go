@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -39,8 +40,58 @@ type PullRequest struct {
 }
 
 type PullRequestReviewer struct {
-	Vote        int64
-	DisplayName string
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	Vote        int64  `json:"vote"`
+	IsRequired  bool   `json:"isRequired"`
+	IsFlagged   bool   `json:"isFlagged"`
+	IsContainer bool   `json:"isContainer"`
+	HasDeclined bool   `json:"hasDeclined"`
+
+	// groups the reviewer has voted for
+	VotedFor []PullRequestReviewer `json:"votedFor"`
+}
+
+type PullRequestThreadList struct {
+	Count int                 `json:"count"`
+	List  []PullRequestThread `json:"value"`
+}
+
+type PullRequestThread struct {
+	Id              int64     `json:"id"`
+	Status          string    `json:"status"`
+	IsDeleted       bool      `json:"isDeleted"`
+	PublishedDate   time.Time `json:"publishedDate"`
+	LastUpdatedDate time.Time `json:"lastUpdatedDate"`
+
+	Properties map[string]PullRequestThreadProperty `json:"properties"`
+
+	Comments []PullRequestThreadComment `json:"comments"`
+}
+
+type PullRequestThreadProperty struct {
+	Type  string      `json:"$type"`
+	Value interface{} `json:"$value"`
+}
+
+type PullRequestThreadComment struct {
+	Id            int64       `json:"id"`
+	Author        IdentifyRef `json:"author"`
+	CommentType   string      `json:"commentType"`
+	IsDeleted     bool        `json:"isDeleted"`
+	PublishedDate time.Time   `json:"publishedDate"`
+}
+
+type PullRequestIterationList struct {
+	Count int                    `json:"count"`
+	List  []PullRequestIteration `json:"value"`
+}
+
+type PullRequestIteration struct {
+	Id          int64     `json:"id"`
+	Reason      string    `json:"reason"`
+	CreatedDate time.Time `json:"createdDate"`
 }
 
 type PullRequestLabels struct {
@@ -96,6 +147,96 @@ func (v *PullRequestVoteSummary) HumanizeString() (status string) {
 	return
 }
 
+// Type returns "group" for group (container) reviewers, otherwise "user"
+func (r *PullRequestReviewer) Type() string {
+	if r.IsContainer {
+		return "group"
+	}
+	return "user"
+}
+
+// IsPending returns true if the reviewer has neither voted nor declined the review
+func (r *PullRequestReviewer) IsPending() bool {
+	return r.Vote == 0 && !r.HasDeclined
+}
+
+// Groups returns the names of the groups the reviewer has voted for
+func (r *PullRequestReviewer) Groups() (ret []string) {
+	for _, group := range r.VotedFor {
+		ret = append(ret, group.DisplayName)
+	}
+	return
+}
+
+// IsCommentThread returns true for threads started by users (non system threads)
+func (t *PullRequestThread) IsCommentThread() bool {
+	return !t.IsDeleted && len(t.Comments) > 0 && t.Comments[0].CommentType == "text"
+}
+
+// IsVoteUpdate returns true for system threads created by reviewer votes
+func (t *PullRequestThread) IsVoteUpdate() bool {
+	if val, exists := t.Properties["CodeReviewThreadType"]; exists {
+		return fmt.Sprintf("%v", val.Value) == "VoteUpdate"
+	}
+	return false
+}
+
+// VoteResult returns the vote of a vote update thread
+func (t *PullRequestThread) VoteResult() int64 {
+	if val, exists := t.Properties["CodeReviewVoteResult"]; exists {
+		if vote, err := strconv.ParseInt(fmt.Sprintf("%v", val.Value), 10, 64); err == nil {
+			return vote
+		}
+	}
+	return 0
+}
+
+// Author returns the author of the first thread comment
+func (t *PullRequestThread) Author() IdentifyRef {
+	if len(t.Comments) > 0 {
+		return t.Comments[0].Author
+	}
+	return IdentifyRef{}
+}
+
+// FirstReviewTime returns the time of the first comment or vote of a user other than the pull request creator
+func (v *PullRequest) FirstReviewTime(threadList PullRequestThreadList) (ret *time.Time) {
+	for _, thread := range threadList.List {
+		if !thread.IsCommentThread() && !thread.IsVoteUpdate() {
+			continue
+		}
+
+		if thread.Author().Id == v.CreatedBy.Id {
+			continue
+		}
+
+		if ret == nil || thread.PublishedDate.Before(*ret) {
+			publishedDate := thread.PublishedDate
+			ret = &publishedDate
+		}
+	}
+	return
+}
+
+// FirstApprovalTime returns the time of the first approving vote (approved or approved with suggestions) of a user other than the pull request creator
+func (v *PullRequest) FirstApprovalTime(threadList PullRequestThreadList) (ret *time.Time) {
+	for _, thread := range threadList.List {
+		if !thread.IsVoteUpdate() || thread.VoteResult() < 5 {
+			continue
+		}
+
+		if thread.Author().Id == v.CreatedBy.Id {
+			continue
+		}
+
+		if ret == nil || thread.PublishedDate.Before(*ret) {
+			publishedDate := thread.PublishedDate
+			ret = &publishedDate
+		}
+	}
+	return
+}
+
 func (c *AzureDevopsClient) ListPullrequest(project, repositoryId string) (list PullRequestList, error error) {
 	defer c.concurrencyUnlock()
 	c.concurrencyLock()
@@ -121,3 +262,95 @@ func (c *AzureDevopsClient) ListPullrequest(project, repositoryId string) (list
 
 	return
 }
+
+func (c *AzureDevopsClient) ListPullrequestHistory(project, repositoryId, status string, minTime time.Time) (list PullRequestList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/git/repositories/%v/pullrequests?api-version=%v&searchCriteria.status=%v&$top=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repositoryId),
+		url.QueryEscape(c.ApiVersion),
+		url.QueryEscape(status),
+		url.QueryEscape(int64ToString(c.LimitPullRequestsPerRepository)),
+	)
+
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	// filter pull requests closed before minTime
+	var filteredList PullRequestList
+	for _, pullRequest := range list.List {
+		if pullRequest.ClosedDate.After(minTime) {
+			filteredList.List = append(filteredList.List, pullRequest)
+		}
+	}
+	filteredList.Count = len(filteredList.List)
+	list = filteredList
+
+	return
+}
+
+func (c *AzureDevopsClient) ListPullrequestThreads(project, repositoryId string, pullRequestId int64) (list PullRequestThreadList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/git/repositories/%v/pullRequests/%v/threads?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repositoryId),
+		url.QueryEscape(int64ToString(pullRequestId)),
+		url.QueryEscape(c.ApiVersion),
+	)
+
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListPullrequestIterations(project, repositoryId string, pullRequestId int64) (list PullRequestIterationList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/git/repositories/%v/pullRequests/%v/iterations?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repositoryId),
+		url.QueryEscape(int64ToString(pullRequestId)),
+		url.QueryEscape(c.ApiVersion),
+	)
+
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
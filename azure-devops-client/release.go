@@ -47,9 +47,10 @@ type Release struct {
 }
 
 type ReleaseArtifact struct {
-	SourceId string `json:"sourceId"`
-	Type     string `json:"type"`
-	Alias    string `json:"alias"`
+	SourceId  string `json:"sourceId"`
+	Type      string `json:"type"`
+	Alias     string `json:"alias"`
+	IsPrimary bool   `json:"isPrimary"`
 
 	DefinitionReference struct {
 		Definition struct {
@@ -104,17 +105,45 @@ type ReleaseEnvironment struct {
 type ReleaseEnvironmentDeployStep struct {
 	Id              int64
 	DeploymentId    int64
-	Attemt          int64
+	Attempt         int64 `json:"attempt"`
 	Reason          string
 	Status          string
 	OperationStatus string
 
 	ReleaseDeployPhases []ReleaseEnvironmentDeployStepPhase
 
+	PreDeploymentGates  ReleaseGates `json:"preDeploymentGates"`
+	PostDeploymentGates ReleaseGates `json:"postDeploymentGates"`
+
 	QueuedOn       time.Time
 	LastModifiedOn time.Time
 }
 
+type ReleaseGates struct {
+	Id     int64  `json:"id"`
+	Status string `json:"status"`
+
+	DeploymentJobs []struct {
+		Tasks []ReleaseGateTask `json:"tasks"`
+	} `json:"deploymentJobs"`
+
+	IgnoredGates []struct {
+		Name string `json:"name"`
+	} `json:"ignoredGates"`
+
+	StartedOn                *time.Time `json:"startedOn"`
+	LastModifiedOn           *time.Time `json:"lastModifiedOn"`
+	StabilizationCompletedOn *time.Time `json:"stabilizationCompletedOn"`
+	SucceedingSince          *time.Time `json:"succeedingSince"`
+}
+
+type ReleaseGateTask struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	StartTime  *time.Time `json:"startTime"`
+	FinishTime *time.Time `json:"finishTime"`
+}
+
 type ReleaseEnvironmentDeployStepPhase struct {
 	Id        int64
 	PhaseId   string
@@ -144,6 +173,47 @@ type ReleaseEnvironmentApproval struct {
 	ModifiedOn time.Time `json:"modifiedOn"`
 }
 
+// LatestDeployStep returns the latest deployment attempt of the environment
+func (e *ReleaseEnvironment) LatestDeployStep() *ReleaseEnvironmentDeployStep {
+	var ret *ReleaseEnvironmentDeployStep
+	for i, deployStep := range e.DeploySteps {
+		if ret == nil || deployStep.Attempt > ret.Attempt {
+			ret = &e.DeploySteps[i]
+		}
+	}
+	return ret
+}
+
+// IsProcessed returns true if the gates were processed (enabled gates in this deployment attempt)
+func (g *ReleaseGates) IsProcessed() bool {
+	return g.Id > 0 && g.StartedOn != nil
+}
+
+// WaitDuration returns the duration the deployment waited for the gates (until stabilization or last evaluation)
+func (g *ReleaseGates) WaitDuration() *time.Duration {
+	if g.StartedOn == nil {
+		return nil
+	}
+
+	var finishTime *time.Time
+	switch {
+	case g.StabilizationCompletedOn != nil:
+		finishTime = g.StabilizationCompletedOn
+	case g.Status != "inProgress" && g.Status != "pending" && g.LastModifiedOn != nil:
+		finishTime = g.LastModifiedOn
+	default:
+		now := time.Now()
+		finishTime = &now
+	}
+
+	if finishTime.Before(*g.StartedOn) {
+		return nil
+	}
+
+	ret := finishTime.Sub(*g.StartedOn)
+	return &ret
+}
+
 func (r *Release) QueueDuration() time.Duration {
 	return r.StartTime.Sub(r.QueueTime)
 }
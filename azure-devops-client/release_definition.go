@@ -36,6 +36,51 @@ type ReleaseDefinitionEnvironment struct {
 	} `json:"currentRelease"`
 
 	BadgeUrl string `json:"badgeUrl"`
+
+	PreDeploymentGates  ReleaseDefinitionGates `json:"preDeploymentGates"`
+	PostDeploymentGates ReleaseDefinitionGates `json:"postDeploymentGates"`
+
+	Conditions []ReleaseDefinitionCondition `json:"conditions"`
+	Schedules  []ReleaseDefinitionSchedule  `json:"schedules"`
+}
+
+type ReleaseDefinitionGates struct {
+	Id int64 `json:"id"`
+
+	GatesOptions *struct {
+		IsEnabled              bool  `json:"isEnabled"`
+		Timeout                int64 `json:"timeout"`
+		SamplingInterval       int64 `json:"samplingInterval"`
+		StabilizationTime      int64 `json:"stabilizationTime"`
+		MinimumSuccessDuration int64 `json:"minimumSuccessDuration"`
+	} `json:"gatesOptions"`
+
+	Gates []struct {
+		Tasks []struct {
+			Name    string `json:"name"`
+			TaskId  string `json:"taskId"`
+			Enabled bool   `json:"enabled"`
+		} `json:"tasks"`
+	} `json:"gates"`
+}
+
+type ReleaseDefinitionCondition struct {
+	Name          string `json:"name"`
+	ConditionType string `json:"conditionType"`
+	Value         string `json:"value"`
+}
+
+type ReleaseDefinitionSchedule struct {
+	DaysToRelease           string `json:"daysToRelease"`
+	StartHours              int64  `json:"startHours"`
+	StartMinutes            int64  `json:"startMinutes"`
+	TimeZoneId              string `json:"timeZoneId"`
+	ScheduleOnlyWithChanges bool   `json:"scheduleOnlyWithChanges"`
+}
+
+// IsEnabled returns true if gates are configured and enabled
+func (g *ReleaseDefinitionGates) IsEnabled() bool {
+	return g.GatesOptions != nil && g.GatesOptions.IsEnabled
 }
 
 func (c *AzureDevopsClient) ListReleaseDefinitions(project string) (list ReleaseDefinitionList, error error) {
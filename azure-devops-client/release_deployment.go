@@ -71,6 +71,25 @@ func (d *ReleaseDeployment) ApprovedBy() string {
 	return strings.Join(approverList[:], ",")
 }
 
+// BuildArtifact returns the primary (or first) build artifact of the deployed release
+func (d *ReleaseDeployment) BuildArtifact() *ReleaseArtifact {
+	var ret *ReleaseArtifact
+	for i, artifact := range d.Artifacts {
+		if artifact.Type != "Build" {
+			continue
+		}
+
+		if artifact.IsPrimary {
+			return &d.Artifacts[i]
+		}
+
+		if ret == nil {
+			ret = &d.Artifacts[i]
+		}
+	}
+	return ret
+}
+
 func (d *ReleaseDeployment) QueuedOnTime() *time.Time {
 	return parseTime(d.QueuedOn)
 }
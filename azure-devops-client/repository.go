@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,8 @@ type Repository struct {
 	Visibility string
 	Size       int64
 
+	DefaultBranch string `json:"defaultBranch"`
+
 	IsDisabled *bool `json:"isDisabled"`
 
 	Links Links `json:"_links"`
@@ -57,6 +60,19 @@ type RepositoryPush struct {
 	PushId int64
 }
 
+type RepositoryBranchStatsList struct {
+	Count int                     `json:"count"`
+	List  []RepositoryBranchStats `json:"value"`
+}
+
+type RepositoryBranchStats struct {
+	Name          string           `json:"name"`
+	AheadCount    int64            `json:"aheadCount"`
+	BehindCount   int64            `json:"behindCount"`
+	IsBaseVersion bool             `json:"isBaseVersion"`
+	Commit        RepositoryCommit `json:"commit"`
+}
+
 func (c *AzureDevopsClient) ListRepositories(project string) (list RepositoryList, error error) {
 	defer c.concurrencyUnlock()
 	c.concurrencyLock()
@@ -131,6 +147,32 @@ func (c *AzureDevopsClient) ListPushes(project string, repository string, fromDa
 	return
 }
 
+func (c *AzureDevopsClient) ListRepositoryBranchStats(project string, repository string) (list RepositoryBranchStatsList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/git/repositories/%v/stats/branches?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(repository),
+		url.QueryEscape(c.ApiVersion),
+	)
+
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
 func (r *Repository) Disabled() (ret bool) {
 	if r.IsDisabled != nil {
 		return *r.IsDisabled
@@ -138,3 +180,8 @@ func (r *Repository) Disabled() (ret bool) {
 
 	return false
 }
+
+// DefaultBranchName returns the default branch without refs/heads/ prefix
+func (r *Repository) DefaultBranchName() string {
+	return strings.TrimPrefix(r.DefaultBranch, "refs/heads/")
+}
@@ -0,0 +1,144 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ServiceEndpointApiVersion        = "7.1"
+	ServiceEndpointHistoryApiVersion = "7.1-preview.1"
+)
+
+type ServiceEndpointList struct {
+	Count int               `json:"count"`
+	List  []ServiceEndpoint `json:"value"`
+}
+
+type ServiceEndpoint struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Url         string `json:"url"`
+	Owner       string `json:"owner"`
+	Description string `json:"description"`
+	IsShared    bool   `json:"isShared"`
+	IsReady     bool   `json:"isReady"`
+	IsOutdated  bool   `json:"isOutdated"`
+
+	Authorization struct {
+		Scheme     string            `json:"scheme"`
+		Parameters map[string]string `json:"parameters"`
+	} `json:"authorization"`
+
+	CreatedBy IdentifyRef `json:"createdBy"`
+
+	ServiceEndpointProjectReferences []struct {
+		Name             string `json:"name"`
+		ProjectReference struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"projectReference"`
+	} `json:"serviceEndpointProjectReferences"`
+}
+
+type ServiceEndpointExecutionRecordList struct {
+	Count int                              `json:"count"`
+	List  []ServiceEndpointExecutionRecord `json:"value"`
+}
+
+type ServiceEndpointExecutionRecord struct {
+	EndpointId string `json:"endpointId"`
+	Data       struct {
+		Id         int64      `json:"id"`
+		PlanType   string     `json:"planType"`
+		Result     string     `json:"result"`
+		StartTime  *time.Time `json:"startTime"`
+		FinishTime *time.Time `json:"finishTime"`
+
+		Definition struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"definition"`
+
+		Owner struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"owner"`
+	} `json:"data"`
+}
+
+// ServicePrincipalId returns the application (client) id of service principal (secret, certificate or workload identity) based endpoints
+func (e *ServiceEndpoint) ServicePrincipalId() string {
+	switch strings.ToLower(e.Authorization.Scheme) {
+	case "serviceprincipal", "workloadidentityfederation":
+		for key, value := range e.Authorization.Parameters {
+			if strings.EqualFold(key, "serviceprincipalid") {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// LastUsed returns the time of the latest execution (finish or start time)
+func (r *ServiceEndpointExecutionRecord) LastUsed() *time.Time {
+	if r.Data.FinishTime != nil {
+		return r.Data.FinishTime
+	}
+	return r.Data.StartTime
+}
+
+func (c *AzureDevopsClient) ListServiceEndpoints(project string) (list ServiceEndpointList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/serviceendpoint/endpoints?includeDetails=true&api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(ServiceEndpointApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// ListServiceEndpointExecutionHistory returns the latest executions (newest first) using the service endpoint
+func (c *AzureDevopsClient) ListServiceEndpointExecutionHistory(project string, serviceEndpointId string, top int64) (list ServiceEndpointExecutionRecordList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/serviceendpoint/%v/executionhistory?top=%v&api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(serviceEndpointId),
+		url.QueryEscape(int64ToString(top)),
+		url.QueryEscape(c.apiVersion(ServiceEndpointHistoryApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
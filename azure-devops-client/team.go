@@ -0,0 +1,191 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type TeamList struct {
+	Count int    `json:"count"`
+	List  []Team `json:"value"`
+}
+
+type Team struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ProjectId   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+}
+
+type TeamMemberList struct {
+	Count int          `json:"count"`
+	List  []TeamMember `json:"value"`
+}
+
+type TeamMember struct {
+	IsTeamAdmin bool        `json:"isTeamAdmin"`
+	Identity    IdentifyRef `json:"identity"`
+}
+
+type TeamFieldValues struct {
+	Field struct {
+		ReferenceName string `json:"referenceName"`
+		Url           string `json:"url"`
+	} `json:"field"`
+
+	DefaultValue string `json:"defaultValue"`
+
+	Values []struct {
+		Value           string `json:"value"`
+		IncludeChildren bool   `json:"includeChildren"`
+	} `json:"values"`
+}
+
+type TeamIterationList struct {
+	Count int             `json:"count"`
+	List  []TeamIteration `json:"value"`
+}
+
+type TeamIteration struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+
+	Attributes struct {
+		StartDate  *time.Time `json:"startDate"`
+		FinishDate *time.Time `json:"finishDate"`
+		TimeFrame  string     `json:"timeFrame"`
+	} `json:"attributes"`
+}
+
+func (c *AzureDevopsClient) ListTeams(project string) (list TeamList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	// teams are paged using $top and $skip
+	top := int64(1000)
+	for skip := int64(0); ; skip += top {
+		url := fmt.Sprintf(
+			"_apis/projects/%v/teams?$top=%v&$skip=%v&api-version=%v",
+			url.QueryEscape(project),
+			url.QueryEscape(int64ToString(top)),
+			url.QueryEscape(int64ToString(skip)),
+			url.QueryEscape(c.ApiVersion),
+		)
+		response, err := c.rest().R().Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList TeamList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		if int64(len(tmpList.List)) < top {
+			break
+		}
+	}
+
+	return
+}
+
+func (c *AzureDevopsClient) ListTeamMembers(project string, team string) (list TeamMemberList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	// members are paged using $top and $skip
+	top := int64(1000)
+	for skip := int64(0); ; skip += top {
+		url := fmt.Sprintf(
+			"_apis/projects/%v/teams/%v/members?$top=%v&$skip=%v&api-version=%v",
+			url.QueryEscape(project),
+			url.QueryEscape(team),
+			url.QueryEscape(int64ToString(top)),
+			url.QueryEscape(int64ToString(skip)),
+			url.QueryEscape(c.ApiVersion),
+		)
+		response, err := c.rest().R().Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList TeamMemberList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		if int64(len(tmpList.List)) < top {
+			break
+		}
+	}
+
+	return
+}
+
+// GetTeamFieldValues returns the team field (usually area paths) assigned to the team
+func (c *AzureDevopsClient) GetTeamFieldValues(project string, team string) (values TeamFieldValues, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/%v/_apis/work/teamsettings/teamfieldvalues?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(team),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &values)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
+
+// ListTeamIterations returns the iterations (sprints) selected by the team
+func (c *AzureDevopsClient) ListTeamIterations(project string, team string) (list TeamIterationList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/%v/_apis/work/teamsettings/iterations?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(team),
+		url.QueryEscape(c.ApiVersion),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
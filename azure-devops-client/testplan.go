@@ -0,0 +1,250 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	TestPlanApiVersion = "7.1"
+)
+
+type TestPlanList struct {
+	Count int        `json:"count"`
+	List  []TestPlan `json:"value"`
+}
+
+type TestPlan struct {
+	Id        int64  `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	AreaPath  string `json:"areaPath"`
+	Iteration string `json:"iteration"`
+	Revision  int64  `json:"revision"`
+
+	Owner IdentifyRef `json:"owner"`
+
+	RootSuite struct {
+		Id int64 `json:"id"`
+	} `json:"rootSuite"`
+
+	StartDate   *time.Time `json:"startDate"`
+	EndDate     *time.Time `json:"endDate"`
+	UpdatedDate *time.Time `json:"updatedDate"`
+}
+
+type TestSuiteList struct {
+	Count int         `json:"count"`
+	List  []TestSuite `json:"value"`
+}
+
+type TestSuite struct {
+	Id        int64  `json:"id"`
+	Name      string `json:"name"`
+	SuiteType string `json:"suiteType"`
+
+	ParentSuite *struct {
+		Id int64 `json:"id"`
+	} `json:"parentSuite"`
+
+	LastUpdatedDate *time.Time `json:"lastUpdatedDate"`
+}
+
+type TestPointList struct {
+	Count int         `json:"count"`
+	List  []TestPoint `json:"value"`
+}
+
+type TestPoint struct {
+	Id          int64       `json:"id"`
+	IsActive    bool        `json:"isActive"`
+	IsAutomated bool        `json:"isAutomated"`
+	Tester      IdentifyRef `json:"tester"`
+
+	Configuration struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"configuration"`
+
+	Results struct {
+		Outcome       string `json:"outcome"`
+		State         string `json:"state"`
+		LastTestRunId int64  `json:"lastTestRunId"`
+
+		LastResultDetails *struct {
+			Duration      float64     `json:"duration"`
+			DateCompleted *time.Time  `json:"dateCompleted"`
+			RunBy         IdentifyRef `json:"runBy"`
+		} `json:"lastResultDetails"`
+	} `json:"results"`
+
+	LastUpdatedDate *time.Time `json:"lastUpdatedDate"`
+}
+
+// Outcome returns the outcome of the latest result of the point (notRun if the point was never executed)
+func (p *TestPoint) Outcome() string {
+	switch p.Results.Outcome {
+	case "", "unspecified", "none", "notExecuted":
+		return "notRun"
+	}
+	return p.Results.Outcome
+}
+
+// LastRun returns the completion time of the latest result of the point
+func (p *TestPoint) LastRun() *time.Time {
+	if p.Results.LastResultDetails == nil || p.Results.LastResultDetails.DateCompleted == nil {
+		return nil
+	}
+
+	if p.Results.LastResultDetails.DateCompleted.IsZero() {
+		return nil
+	}
+
+	return p.Results.LastResultDetails.DateCompleted
+}
+
+// ListTestPlans returns the active test plans of a project
+func (c *AzureDevopsClient) ListTestPlans(project string) (list TestPlanList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/testplan/plans?filterActivePlans=true&includePlanDetails=true&api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(c.apiVersion(TestPlanApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		response, err = c.rest().R().SetQueryParam("continuationToken", continuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList TestPlanList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
+
+// ListTestSuites returns the suites of a test plan
+func (c *AzureDevopsClient) ListTestSuites(project string, testPlanId int64) (list TestSuiteList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/testplan/plans/%v/suites?api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(int64ToString(testPlanId)),
+		url.QueryEscape(c.apiVersion(TestPlanApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		response, err = c.rest().R().SetQueryParam("continuationToken", continuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList TestSuiteList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
+
+// ListTestPoints returns the points (test case and configuration) of a test suite including the latest result
+func (c *AzureDevopsClient) ListTestPoints(project string, testPlanId, testSuiteId int64) (list TestPointList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"%v/_apis/testplan/plans/%v/suites/%v/testpoint?includePointDetails=true&api-version=%v",
+		url.QueryEscape(project),
+		url.QueryEscape(int64ToString(testPlanId)),
+		url.QueryEscape(int64ToString(testSuiteId)),
+		url.QueryEscape(c.apiVersion(TestPlanApiVersion)),
+	)
+	response, err := c.rest().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	continuationToken := response.Header().Get("x-ms-continuationtoken")
+
+	for continuationToken != "" {
+		response, err = c.rest().R().SetQueryParam("continuationToken", continuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList TestPointList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.Count += tmpList.Count
+		list.List = append(list.List, tmpList.List...)
+
+		continuationToken = response.Header().Get("x-ms-continuationtoken")
+	}
+
+	return
+}
@@ -0,0 +1,155 @@
+package AzureDevopsClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	UserEntitlementApiVersion  = "7.1-preview.3"
+	GroupEntitlementApiVersion = "7.1-preview.1"
+)
+
+type UserEntitlementList struct {
+	TotalCount        int64             `json:"totalCount"`
+	ContinuationToken string            `json:"continuationToken"`
+	List              []UserEntitlement `json:"members"`
+}
+
+type UserEntitlement struct {
+	Id string `json:"id"`
+
+	User GraphUser `json:"user"`
+
+	AccessLevel EntitlementAccessLevel `json:"accessLevel"`
+
+	GroupAssignments []struct {
+		Group       GraphUser              `json:"group"`
+		AccessLevel EntitlementAccessLevel `json:"accessLevel"`
+	} `json:"groupAssignments"`
+
+	DateCreated      time.Time `json:"dateCreated"`
+	LastAccessedDate time.Time `json:"lastAccessedDate"`
+}
+
+type EntitlementAccessLevel struct {
+	AccountLicenseType string `json:"accountLicenseType"`
+	MsdnLicenseType    string `json:"msdnLicenseType"`
+	LicensingSource    string `json:"licensingSource"`
+	LicenseDisplayName string `json:"licenseDisplayName"`
+	AssignmentSource   string `json:"assignmentSource"`
+	Status             string `json:"status"`
+	StatusMessage      string `json:"statusMessage"`
+}
+
+type GroupEntitlementList struct {
+	Count int                `json:"count"`
+	List  []GroupEntitlement `json:"value"`
+}
+
+type GroupEntitlement struct {
+	Id     string    `json:"id"`
+	Group  GraphUser `json:"group"`
+	Status string    `json:"status"`
+
+	LicenseRule EntitlementAccessLevel `json:"licenseRule"`
+
+	LastExecuted *time.Time `json:"lastExecuted"`
+}
+
+// LicenseType returns the license type of the access level (account license or visual studio subscription)
+func (a *EntitlementAccessLevel) LicenseType() string {
+	if a.LicensingSource == "msdn" {
+		return "msdn-" + a.MsdnLicenseType
+	}
+	return a.AccountLicenseType
+}
+
+// IsPaid returns true if the access level is a license paid by the organization (Basic, Basic + Test Plans)
+// stakeholders and visual studio subscribers are not billed
+func (a *EntitlementAccessLevel) IsPaid() bool {
+	if a.LicensingSource != "account" {
+		return false
+	}
+
+	switch a.AccountLicenseType {
+	case "express", "advanced", "professional":
+		return true
+	}
+	return false
+}
+
+// LastAccessed returns the last access of the user, nil if the user never accessed the organization
+func (e *UserEntitlement) LastAccessed() *time.Time {
+	if e.LastAccessedDate.IsZero() {
+		return nil
+	}
+	return &e.LastAccessedDate
+}
+
+func (c *AzureDevopsClient) ListUserEntitlements() (list UserEntitlementList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/userentitlements?api-version=%v",
+		url.QueryEscape(c.apiVersion(UserEntitlementApiVersion)),
+	)
+	response, err := c.restVsaex().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	for list.ContinuationToken != "" {
+		response, err = c.restVsaex().R().SetQueryParam("continuationToken", list.ContinuationToken).Get(url)
+		if err := c.checkResponse(response, err); err != nil {
+			error = err
+			return
+		}
+
+		var tmpList UserEntitlementList
+		err = json.Unmarshal(response.Body(), &tmpList)
+		if err != nil {
+			error = err
+			return
+		}
+
+		list.List = append(list.List, tmpList.List...)
+		list.ContinuationToken = tmpList.ContinuationToken
+	}
+
+	return
+}
+
+// ListGroupEntitlements returns the group rules (license and project assignments of groups)
+func (c *AzureDevopsClient) ListGroupEntitlements() (list GroupEntitlementList, error error) {
+	defer c.concurrencyUnlock()
+	c.concurrencyLock()
+
+	url := fmt.Sprintf(
+		"_apis/groupentitlements?api-version=%v",
+		url.QueryEscape(c.apiVersion(GroupEntitlementApiVersion)),
+	)
+	response, err := c.restVsaex().R().Get(url)
+	if err := c.checkResponse(response, err); err != nil {
+		error = err
+		return
+	}
+
+	err = json.Unmarshal(response.Body(), &list)
+	if err != nil {
+		error = err
+		return
+	}
+
+	return
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/webdevops/go-common/azuresdk/armclient"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	cacheStoreProtocolFile          = "file"
+	cacheStoreProtocolAzBlob        = "azblob"
+	cacheStoreProtocolK8sConfigMap  = "k8scm"
+	cacheStoreK8sConfigMapFieldName = "webdevops/common"
+)
+
+type (
+	// cacheStore persists collector state (eg. cursors) which has to survive restarts and cache expiry.
+	// go-common only exposes the cache for collector metrics (Collector.SetCache), so this store mirrors
+	// its backends and storage format (--cache.path) for arbitrary state
+	cacheStore struct {
+		protocol string
+		raw      string
+
+		// file
+		filePath string
+
+		// azblob
+		azblobClient    *azblob.Client
+		azblobContainer string
+		azblobBlob      string
+
+		// kubernetes configmap
+		k8sClient    corev1.ConfigMapInterface
+		k8sConfigMap string
+		k8sNamespace string
+		k8sKey       string
+	}
+)
+
+// newCacheStore creates a state store for the cache path, returns nil if no cache path is set
+func newCacheStore(path *string) (*cacheStore, error) {
+	if path == nil {
+		return nil, nil
+	}
+
+	store := &cacheStore{
+		raw: *path,
+	}
+
+	switch {
+	case strings.HasPrefix(store.raw, "file://"):
+		store.protocol = cacheStoreProtocolFile
+		store.filePath = strings.TrimPrefix(store.raw, "file://")
+	case strings.HasPrefix(store.raw, "azblob://"):
+		store.protocol = cacheStoreProtocolAzBlob
+		parsedUrl, err := url.Parse(store.raw)
+		if err != nil {
+			return nil, err
+		}
+
+		pathParts := strings.Split(strings.TrimPrefix(parsedUrl.Path, "/"), "/")
+		if len(pathParts) < 2 {
+			return nil, fmt.Errorf(`azblob path needs to be specified as azblob://storageaccount.blob.core.windows.net/container/blob, got: %v`, store.raw)
+		}
+		store.azblobContainer = pathParts[0]
+		store.azblobBlob = strings.Join(pathParts[1:], "/")
+
+		azureClient, err := armclient.NewArmClientFromEnvironment(logger)
+		if err != nil {
+			return nil, err
+		}
+
+		azblobOpts := azblob.ClientOptions{ClientOptions: *azureClient.NewAzCoreClientOptions()}
+		client, err := azblob.NewClient(fmt.Sprintf(`https://%v/`, parsedUrl.Hostname()), azureClient.GetCred(), &azblobOpts)
+		if err != nil {
+			return nil, err
+		}
+		store.azblobClient = client
+	case strings.HasPrefix(store.raw, "k8scm://"):
+		store.protocol = cacheStoreProtocolK8sConfigMap
+		parsedUrl, err := url.Parse(store.raw)
+		if err != nil {
+			return nil, err
+		}
+
+		pathParts := strings.SplitN(parsedUrl.Path, "/", 3)
+		if len(pathParts) < 3 {
+			return nil, fmt.Errorf(`k8scm path needs to be specified as k8scm://namespace/name, got: %v`, store.raw)
+		}
+		store.k8sNamespace = parsedUrl.Hostname()
+		// pathParts[0] is always empty, since the .Path begins with an /
+		store.k8sConfigMap = pathParts[1]
+		// slashes are not allowed as key
+		store.k8sKey = strings.ReplaceAll(pathParts[2], "/", "-")
+
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		store.k8sClient = client.CoreV1().ConfigMaps(store.k8sNamespace)
+	default:
+		store.protocol = cacheStoreProtocolFile
+		store.filePath = store.raw
+	}
+
+	return store, nil
+}
+
+// Read restores the stored state into v, returns false if there is no stored state yet
+func (s *cacheStore) Read(ctx context.Context, v interface{}) (bool, error) {
+	var content []byte
+
+	switch s.protocol {
+	case cacheStoreProtocolFile:
+		data, err := os.ReadFile(s.filePath) // #nosec inside container
+		if err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		content = data
+	case cacheStoreProtocolAzBlob:
+		response, err := s.azblobClient.DownloadStream(ctx, s.azblobContainer, s.azblobBlob, nil)
+		if err != nil {
+			if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		defer response.Body.Close() // nolint: errcheck
+
+		data, err := io.ReadAll(response.Body)
+		if err != nil {
+			return false, err
+		}
+		content = data
+	case cacheStoreProtocolK8sConfigMap:
+		configMap, err := s.k8sClient.Get(ctx, s.k8sConfigMap, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		data, exists := configMap.BinaryData[s.k8sKey]
+		if !exists {
+			return false, nil
+		}
+
+		content, err = decodeConfigMapData(data)
+		if err != nil {
+			return false, err
+		}
+	default:
+		return false, errors.New("unsupported cache store protocol")
+	}
+
+	if err := json.Unmarshal(content, v); err != nil {
+		return false, fmt.Errorf(`unable to decode state from "%v": %w`, s.raw, err)
+	}
+
+	return true, nil
+}
+
+// Write persists the state v
+func (s *cacheStore) Write(ctx context.Context, v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch s.protocol {
+	case cacheStoreProtocolFile:
+		dirPath := filepath.Dir(s.filePath)
+		if err := os.MkdirAll(dirPath, 0700); err != nil {
+			return err
+		}
+
+		// write to temp file first and rename afterwards (atomic operation)
+		tmpFilePath := filepath.Join(dirPath, fmt.Sprintf(".%s.tmp", filepath.Base(s.filePath)))
+		if err := os.WriteFile(tmpFilePath, content, 0600); err != nil { // #nosec inside container
+			return err
+		}
+		return os.Rename(tmpFilePath, s.filePath)
+	case cacheStoreProtocolAzBlob:
+		_, err := s.azblobClient.UploadBuffer(ctx, s.azblobContainer, s.azblobBlob, content, nil)
+		return err
+	case cacheStoreProtocolK8sConfigMap:
+		data, err := encodeConfigMapData(content)
+		if err != nil {
+			return err
+		}
+
+		configMap := corev1apply.ConfigMap(s.k8sConfigMap, s.k8sNamespace)
+		configMap.WithBinaryData(map[string][]byte{s.k8sKey: data})
+
+		_, err = s.k8sClient.Apply(ctx, configMap, metav1.ApplyOptions{
+			Force:        false,
+			FieldManager: cacheStoreK8sConfigMapFieldName + "/" + s.k8sKey,
+		})
+		return err
+	}
+
+	return errors.New("unsupported cache store protocol")
+}
+
+// encodeConfigMapData compresses content (gzip, base64) as kubernetes configmaps can only hold 1MB of data in total,
+// same format as the go-common collector cache
+func encodeConfigMapData(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	wb64 := base64.NewEncoder(base64.StdEncoding, &buf)
+	wgz := gzip.NewWriter(wb64)
+	if _, err := wgz.Write(content); err != nil {
+		return nil, err
+	}
+	if err := wgz.Close(); err != nil {
+		return nil, err
+	}
+	if err := wb64.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeConfigMapData decompresses content written by encodeConfigMapData
+func decodeConfigMapData(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() // nolint: errcheck
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"testing"
+)
+
+func TestConfigMapData(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{
+			name:    "empty",
+			content: []byte{},
+		},
+		{
+			name:    "json",
+			content: []byte(`{"timestamp":"2024-05-01T10:00:00Z","eventIds":["a","b"]}`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := encodeConfigMapData(test.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// same format as the go-common collector cache (gzip, base64)
+			r, err := gzip.NewReader(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(content, test.content) {
+				t.Errorf("expected %q, got %q", test.content, content)
+			}
+
+			content, err = decodeConfigMapData(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(content, test.content) {
+				t.Errorf("expected %q, got %q", test.content, content)
+			}
+		})
+	}
+}
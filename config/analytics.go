@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	AnalyticsScopeProject      = "project"
+	AnalyticsScopeOrganization = "organization"
+)
+
+var (
+	analyticsMetricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	analyticsLabelNameRegexp  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+type (
+	AnalyticsConfig struct {
+		Queries []AnalyticsQuery `yaml:"queries"`
+	}
+
+	AnalyticsQuery struct {
+		// prometheus metric name and help text
+		Metric string `yaml:"metric"`
+		Help   string `yaml:"help"`
+
+		// scope of the query: "project" (default, runs for every discovered project) or "organization"
+		Scope string `yaml:"scope"`
+
+		// optional list of project names or UUIDs (only project scope)
+		Projects []string `yaml:"projects"`
+
+		// OData query relative to the _odata endpoint, eg. "PipelineRuns?$apply=groupby(...)"
+		Query string `yaml:"query"`
+
+		// mapping of prometheus label names to result columns (eg. pipelineName: Pipeline/PipelineName)
+		Labels map[string]string `yaml:"labels"`
+
+		// result column used as metric value
+		Value string `yaml:"value"`
+	}
+)
+
+func LoadAnalyticsConfig(path string) (config AnalyticsConfig, err error) {
+	content, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return
+	}
+
+	if err = yaml.Unmarshal(content, &config); err != nil {
+		return
+	}
+
+	metricNames := map[string]bool{}
+	for key, query := range config.Queries {
+		if !analyticsMetricNameRegexp.MatchString(query.Metric) {
+			err = fmt.Errorf("analytics query #%v: invalid metric name \"%v\"", key, query.Metric)
+			return
+		}
+
+		if _, exists := metricNames[query.Metric]; exists {
+			err = fmt.Errorf("analytics query #%v: duplicate metric name \"%v\"", key, query.Metric)
+			return
+		}
+		metricNames[query.Metric] = true
+
+		switch query.Scope {
+		case "":
+			config.Queries[key].Scope = AnalyticsScopeProject
+		case AnalyticsScopeProject, AnalyticsScopeOrganization:
+		default:
+			err = fmt.Errorf("analytics query \"%v\": invalid scope \"%v\" (expected %v or %v)", query.Metric, query.Scope, AnalyticsScopeProject, AnalyticsScopeOrganization)
+			return
+		}
+
+		if query.Query == "" {
+			err = fmt.Errorf("analytics query \"%v\": query is empty", query.Metric)
+			return
+		}
+
+		if query.Value == "" {
+			err = fmt.Errorf("analytics query \"%v\": value column is empty", query.Metric)
+			return
+		}
+
+		for labelName := range query.Labels {
+			if !analyticsLabelNameRegexp.MatchString(labelName) || labelName == "projectID" {
+				err = fmt.Errorf("analytics query \"%v\": invalid label name \"%v\"", query.Metric, labelName)
+				return
+			}
+		}
+
+		if query.Help == "" {
+			config.Queries[key].Help = "Azure DevOps analytics query " + query.Metric
+		}
+	}
+
+	return
+}
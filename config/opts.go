@@ -18,17 +18,32 @@ type (
 
 		// scrape time settings
 		Scrape struct {
-			Time              time.Duration  `long:"scrape.time"                  env:"SCRAPE_TIME"                    description:"Default scrape time (time.duration)"                       default:"30m"`
-			TimeProjects      *time.Duration `long:"scrape.time.projects"         env:"SCRAPE_TIME_PROJECTS"           description:"Scrape time for project metrics (time.duration)"`
-			TimeRepository    *time.Duration `long:"scrape.time.repository"       env:"SCRAPE_TIME_REPOSITORY"         description:"Scrape time for repository metrics (time.duration)"`
-			TimeBuild         *time.Duration `long:"scrape.time.build"            env:"SCRAPE_TIME_BUILD"              description:"Scrape time for build metrics (time.duration)"`
-			TimeRelease       *time.Duration `long:"scrape.time.release"          env:"SCRAPE_TIME_RELEASE"            description:"Scrape time for release metrics (time.duration)"`
-			TimeDeployment    *time.Duration `long:"scrape.time.deployment"       env:"SCRAPE_TIME_DEPLOYMENT"         description:"Scrape time for deployment metrics (time.duration)"`
-			TimePullRequest   *time.Duration `long:"scrape.time.pullrequest"      env:"SCRAPE_TIME_PULLREQUEST"        description:"Scrape time for pullrequest metrics  (time.duration)"`
-			TimeStats         *time.Duration `long:"scrape.time.stats"            env:"SCRAPE_TIME_STATS"              description:"Scrape time for stats metrics  (time.duration)"`
-			TimeResourceUsage *time.Duration `long:"scrape.time.resourceusage"    env:"SCRAPE_TIME_RESOURCEUSAGE"      description:"Scrape time for resourceusage metrics  (time.duration)"`
-			TimeQuery         *time.Duration `long:"scrape.time.query"            env:"SCRAPE_TIME_QUERY"              description:"Scrape time for query results  (time.duration)"`
-			TimeLive          *time.Duration `long:"scrape.time.live"             env:"SCRAPE_TIME_LIVE"               description:"Scrape time for live metrics (time.duration)"              default:"30s"`
+			Time                time.Duration  `long:"scrape.time"                  env:"SCRAPE_TIME"                    description:"Default scrape time (time.duration)"                       default:"30m"`
+			TimeProjects        *time.Duration `long:"scrape.time.projects"         env:"SCRAPE_TIME_PROJECTS"           description:"Scrape time for project metrics (time.duration)"`
+			TimeRepository      *time.Duration `long:"scrape.time.repository"       env:"SCRAPE_TIME_REPOSITORY"         description:"Scrape time for repository metrics (time.duration)"`
+			TimeBuild           *time.Duration `long:"scrape.time.build"            env:"SCRAPE_TIME_BUILD"              description:"Scrape time for build metrics (time.duration)"`
+			TimeRelease         *time.Duration `long:"scrape.time.release"          env:"SCRAPE_TIME_RELEASE"            description:"Scrape time for release metrics (time.duration)"`
+			TimeDeployment      *time.Duration `long:"scrape.time.deployment"       env:"SCRAPE_TIME_DEPLOYMENT"         description:"Scrape time for deployment metrics (time.duration)"`
+			TimePullRequest     *time.Duration `long:"scrape.time.pullrequest"      env:"SCRAPE_TIME_PULLREQUEST"        description:"Scrape time for pullrequest metrics  (time.duration)"`
+			TimeStats           *time.Duration `long:"scrape.time.stats"            env:"SCRAPE_TIME_STATS"              description:"Scrape time for stats metrics  (time.duration)"`
+			TimeResourceUsage   *time.Duration `long:"scrape.time.resourceusage"    env:"SCRAPE_TIME_RESOURCEUSAGE"      description:"Scrape time for resourceusage metrics  (time.duration)"`
+			TimeQuery           *time.Duration `long:"scrape.time.query"            env:"SCRAPE_TIME_QUERY"              description:"Scrape time for query results  (time.duration)"`
+			TimeAnalytics       *time.Duration `long:"scrape.time.analytics"        env:"SCRAPE_TIME_ANALYTICS"          description:"Scrape time for analytics query results  (time.duration)"`
+			TimePolicy          *time.Duration `long:"scrape.time.policy"           env:"SCRAPE_TIME_POLICY"             description:"Scrape time for branch policy metrics  (time.duration)"`
+			TimeDeploymentGroup *time.Duration `long:"scrape.time.deploymentgroup"  env:"SCRAPE_TIME_DEPLOYMENTGROUP"    description:"Scrape time for deployment group metrics  (time.duration)"`
+			TimePipeline        *time.Duration `long:"scrape.time.pipeline"         env:"SCRAPE_TIME_PIPELINE"           description:"Scrape time for pipeline (runs) metrics  (time.duration)"`
+			TimeDora            *time.Duration `long:"scrape.time.dora"             env:"SCRAPE_TIME_DORA"               description:"Scrape time for DORA metrics  (time.duration)"`
+			TimeServiceEndpoint *time.Duration `long:"scrape.time.serviceendpoint"  env:"SCRAPE_TIME_SERVICEENDPOINT"    description:"Scrape time for service endpoint (service connection) metrics  (time.duration)"`
+			TimeToken           *time.Duration `long:"scrape.time.token"            env:"SCRAPE_TIME_TOKEN"              description:"Scrape time for personal access token metrics  (time.duration)"`
+			TimeLibrary         *time.Duration `long:"scrape.time.library"          env:"SCRAPE_TIME_LIBRARY"            description:"Scrape time for library (variable groups, secure files) metrics  (time.duration)"`
+			TimeAudit           *time.Duration `long:"scrape.time.audit"            env:"SCRAPE_TIME_AUDIT"              description:"Scrape time for audit log metrics  (time.duration)"`
+			TimeUserEntitlement *time.Duration `long:"scrape.time.userentitlement"  env:"SCRAPE_TIME_USERENTITLEMENT"    description:"Scrape time for user entitlement (access level, license) metrics  (time.duration)"`
+			TimeFeed            *time.Duration `long:"scrape.time.feed"             env:"SCRAPE_TIME_FEED"               description:"Scrape time for artifacts feed and package metrics  (time.duration)"`
+			TimeAdvSecurity     *time.Duration `long:"scrape.time.advsecurity"      env:"SCRAPE_TIME_ADVSECURITY"        description:"Scrape time for advanced security alert metrics  (time.duration)"`
+			TimeTestPlan        *time.Duration `long:"scrape.time.testplan"         env:"SCRAPE_TIME_TESTPLAN"           description:"Scrape time for test plan (manual test execution) metrics  (time.duration)"`
+			TimeTeam            *time.Duration `long:"scrape.time.team"             env:"SCRAPE_TIME_TEAM"               description:"Scrape time for team metrics (members, area and iteration paths)  (time.duration)"`
+			TimeElasticPool     *time.Duration `long:"scrape.time.elasticpool"      env:"SCRAPE_TIME_ELASTICPOOL"        description:"Scrape time for elastic agent pool metrics (time.duration)"  default:"5m"`
+			TimeLive            *time.Duration `long:"scrape.time.live"             env:"SCRAPE_TIME_LIVE"               description:"Scrape time for live metrics (time.duration)"              default:"30s"`
 		}
 
 		// summary options
@@ -49,10 +64,12 @@ type (
 			AccessToken     string  `long:"azuredevops.access-token"            env:"AZURE_DEVOPS_ACCESS_TOKEN"      description:"Azure DevOps access token" json:"-"`
 			AccessTokenFile *string `long:"azuredevops.access-token-file"       env:"AZURE_DEVOPS_ACCESS_TOKEN_FILE" description:"Azure DevOps access token (from file)"`
 			Organisation    string  `long:"azuredevops.organisation"            env:"AZURE_DEVOPS_ORGANISATION"      description:"Azure DevOps organization" required:"true"`
-			ApiVersion      string  `long:"azuredevops.apiversion"              env:"AZURE_DEVOPS_APIVERSION"        description:"Azure DevOps API version"  default:"5.1"`
+			ApiVersion      string  `long:"azuredevops.apiversion"              env:"AZURE_DEVOPS_APIVERSION"        description:"Azure DevOps API version (resources not available in this version use their minimum required version)"  default:"5.1"`
 
 			// agentpool
-			AgentPoolIdList *[]int64 `long:"azuredevops.agentpool"  env:"AZURE_DEVOPS_AGENTPOOL"  env-delim:" "   description:"Enable scrape metrics for agent pool (IDs)"`
+			AgentPoolIdList           *[]int64  `long:"azuredevops.agentpool"  env:"AZURE_DEVOPS_AGENTPOOL"  env-delim:" "   description:"Enable scrape metrics for agent pool (IDs)"`
+			AgentPoolCapabilitySchema *[]string `long:"agentpool.capability"  env:"AZURE_DEVOPS_AGENTPOOL_CAPABILITY"  env-delim:" "   description:"Agent capabilities (system and user) to be exported in the format 'capabilityName:type' with following types: number, info, bool"`
+			AgentPoolBillingDay       int       `long:"agentpool.billing-day"  env:"AZURE_DEVOPS_AGENTPOOL_BILLING_DAY"                 description:"Day of month the billing period starts (1-28) for job minute consumption"  default:"1"`
 
 			// ignore settings
 			FilterProjects    []string `long:"whitelist.project"    env:"AZURE_DEVOPS_FILTER_PROJECT"    env-delim:" "   description:"Filter projects (UUIDs)"`
@@ -60,6 +77,9 @@ type (
 
 			FilterTimelineState  []string `long:"timeline.state"    env:"AZURE_DEVOPS_FILTER_TIMELINE_STATE"    env-delim:" "   description:"Filter timeline states (completed, inProgress, pending)" default:"completed"`
 			FetchAllBuildsFilter []string `long:"builds.all.project"   env:"AZURE_DEVOPS_FETCH_ALL_BUILDS_FILTER_PROJECT"  env-delim:" "  description:"Fetch all builds from projects (UUIDs or names)"`
+			FetchBuildArtifacts  bool     `long:"builds.artifacts"     env:"AZURE_DEVOPS_FETCH_BUILD_ARTIFACTS"                           description:"Fetch artifacts and retention leases of completed builds"`
+			FetchBuildIssues     bool     `long:"builds.issues"        env:"AZURE_DEVOPS_FETCH_BUILD_ISSUES"                              description:"Fetch timeline issues (errors, warnings) of failed build tasks"`
+			BuildIssueLogPattern []string `long:"builds.issues.log-pattern"   env:"AZURE_DEVOPS_BUILD_ISSUES_LOG_PATTERN"  env-delim:" "  description:"Patterns (regexp) for log lines of failed build tasks to be collected as issues (requires builds.issues)"`
 
 			// query settings
 			QueriesWithProjects []string `long:"list.query"    env:"AZURE_DEVOPS_QUERIES"    env-delim:" "   description:"Pairs of query and project UUIDs in the form: '<queryId>@<projectId>'"`
@@ -67,6 +87,67 @@ type (
 			// tag settings
 			TagsSchema                *[]string `long:"tags.schema"             env:"AZURE_DEVOPS_TAG_SCHEMA"              env-delim:" "   description:"Tags to be extracted from builds in the format 'tagName:type' with following types: number, info, bool"`
 			TagsBuildDefinitionIdList *[]int64  `long:"tags.build.definition"   env:"AZURE_DEVOPS_TAG_BUILD_DEFINITION"    env-delim:" "   description:"Build definition ids to query tags (IDs)"`
+
+			// repository settings
+			RepositoryBranchPattern       []string      `long:"repository.branch.pattern"          env:"AZURE_DEVOPS_REPOSITORY_BRANCH_PATTERN"          env-delim:" "   description:"Branch name patterns (regexp) for ahead/behind metrics against the default branch"`
+			RepositoryBranchStaleDuration time.Duration `long:"repository.branch.stale-duration"   env:"AZURE_DEVOPS_REPOSITORY_BRANCH_STALE_DURATION"                   description:"Time (time.Duration) without commit after which a branch is considered stale"  default:"2160h"`
+
+			// deployment group settings
+			DeploymentGroupEnabled bool `long:"deploymentgroup.enabled"  env:"AZURE_DEVOPS_DEPLOYMENTGROUP_ENABLED"  description:"Enable deployment group (targets, deployments) metrics"`
+
+			// pipeline settings
+			PipelineEnabled         bool     `long:"pipeline.enabled"           env:"AZURE_DEVOPS_PIPELINE_ENABLED"                           description:"Enable pipeline (runs, environments) metrics"`
+			PipelineParameterValues []string `long:"pipeline.parameter-values"  env:"AZURE_DEVOPS_PIPELINE_PARAMETER_VALUES"  env-delim:" "  description:"Template parameter names whose values are exported in pipeline run parameter metrics (values of other parameters are empty)"`
+
+			// service endpoint settings
+			ServiceEndpointEnabled      bool `long:"serviceendpoint.enabled"        env:"AZURE_DEVOPS_SERVICEENDPOINT_ENABLED"        description:"Enable service endpoint (service connection) metrics"`
+			ServiceEndpointSecretExpiry bool `long:"serviceendpoint.secret-expiry"  env:"AZURE_DEVOPS_SERVICEENDPOINT_SECRET_EXPIRY"  description:"Fetch secret and certificate expiry of service principals behind service endpoints from Microsoft Graph (requires azure authentication instead of access token and Application.Read.All)"`
+
+			// library settings
+			LibraryEnabled bool `long:"library.enabled"  env:"AZURE_DEVOPS_LIBRARY_ENABLED"  description:"Enable library (variable groups, secure files) metrics"`
+
+			// token settings
+			FetchTokenExpiry bool `long:"tokens.expiry"  env:"AZURE_DEVOPS_FETCH_TOKEN_EXPIRY"  description:"Fetch expiry of personal access tokens (exporter identity and organization users) using the token administration api"`
+
+			// user entitlement settings
+			UserEntitlementEnabled bool          `long:"userentitlement.enabled"  env:"AZURE_DEVOPS_USERENTITLEMENT_ENABLED"  description:"Enable user entitlement (access level, license) metrics (requires organization level permissions)"`
+			UserInactiveDuration   time.Duration `long:"users.inactive-duration"  env:"AZURE_DEVOPS_USERS_INACTIVE_DURATION"  description:"Time (time.Duration) without access after which an user with paid access level is considered inactive"  default:"2160h"`
+
+			// feed settings
+			FeedEnabled bool `long:"feed.enabled"  env:"AZURE_DEVOPS_FEED_ENABLED"  description:"Enable artifacts feed and package metrics"`
+
+			// advanced security settings
+			AdvSecurityEnabled bool `long:"advsecurity.enabled"  env:"AZURE_DEVOPS_ADVSECURITY_ENABLED"  description:"Enable advanced security alert metrics"`
+
+			// test plan settings
+			TestPlanEnabled bool `long:"testplan.enabled"  env:"AZURE_DEVOPS_TESTPLAN_ENABLED"  description:"Enable test plan (manual test execution) metrics"`
+
+			// team settings
+			TeamEnabled bool `long:"team.enabled"  env:"AZURE_DEVOPS_TEAM_ENABLED"  description:"Enable team metrics (members, area and iteration paths)"`
+
+			// policy settings
+			PolicyEnabled  bool     `long:"policy.enabled"     env:"AZURE_DEVOPS_POLICY_ENABLED"                    description:"Enable branch policy and default branch compliance metrics"`
+			PolicyRequired []string `long:"policy.required"    env:"AZURE_DEVOPS_POLICY_REQUIRED"    env-delim:" "   description:"Required branch policies for default branch compliance (minimumReviewers, requiredReviewers, buildValidation, commentResolution, mergeStrategy, workItemLinking, statusCheck)" default:"minimumReviewers" default:"buildValidation"`
+		}
+
+		// analytics settings
+		Analytics struct {
+			Config string `long:"analytics.config"  env:"AZURE_DEVOPS_ANALYTICS_CONFIG"  description:"Path to analytics (OData) query config file (yaml)"`
+		}
+
+		// dora settings
+		Dora struct {
+			Enabled         bool          `long:"dora.enabled"           env:"AZURE_DEVOPS_DORA_ENABLED"                     description:"Enable DORA metrics (deployment frequency, lead time, change failure rate, time to restore)"`
+			Environment     []string      `long:"dora.environment"       env:"AZURE_DEVOPS_DORA_ENVIRONMENT"  env-delim:" "  description:"Production environment name patterns (regexp) of release stages and pipeline environments for DORA metrics" default:"(?i)^prod(uction)?$"`
+			Pipeline        []string      `long:"dora.pipeline"          env:"AZURE_DEVOPS_DORA_PIPELINE"     env-delim:" "  description:"Production pipeline and release definition name patterns (regexp) for DORA metrics (all if empty)"`
+			HistoryDuration time.Duration `long:"dora.history-duration"  env:"AZURE_DEVOPS_DORA_HISTORY_DURATION"            description:"Time (time.Duration) how long the exporter should look back for production deployments" default:"720h"`
+		}
+
+		// audit settings
+		Audit struct {
+			Enabled         bool          `long:"audit.enabled"           env:"AZURE_DEVOPS_AUDIT_ENABLED"                          description:"Enable audit log metrics (requires View audit log permission)"`
+			Alert           []string      `long:"audit.alert"             env:"AZURE_DEVOPS_AUDIT_ALERT"             env-delim:" "  description:"Sensitive audit actions exported as alert metrics in the format 'alertName:actionIdPattern' (regexp)" default:"permissionChange:^Security\\.(Modify|Remove|Reset)" default:"policyBypass:^Git\\.RefUpdatePoliciesBypassed$" default:"patCreated:^Token\\.PatCreateEvent$"`
+			HistoryDuration time.Duration `long:"audit.history-duration"  env:"AZURE_DEVOPS_AUDIT_HISTORY_DURATION"                 description:"Time (time.Duration) how long the exporter should look back for audit events without stored cursor" default:"1h"`
 		}
 
 		// cache settings
@@ -93,6 +174,8 @@ type (
 			ReleaseDefinitionsPerProject int64         `long:"limit.releasedefinitions-per-project"  env:"LIMIT_RELEASEDEFINITION_PER_PROJECT"   description:"Limit builds per definition"      default:"100"`
 			BuildHistoryDuration         time.Duration `long:"limit.build-history-duration"          env:"LIMIT_BUILD_HISTORY_DURATION"          description:"Time (time.Duration) how long the exporter should look back for builds"      default:"48h"`
 			ReleaseHistoryDuration       time.Duration `long:"limit.release-history-duration"        env:"LIMIT_RELEASE_HISTORY_DURATION"        description:"Time (time.Duration) how long the exporter should look back for releases"      default:"48h"`
+			PullRequestsPerRepository    int64         `long:"limit.pullrequests-per-repository"     env:"LIMIT_PULLREQUESTS_PER_REPOSITORY"     description:"Limit closed (completed, abandoned) pullrequests per repository" default:"100"`
+			PullRequestHistoryDuration   time.Duration `long:"limit.pullrequest-history-duration"    env:"LIMIT_PULLREQUEST_HISTORY_DURATION"    description:"Time (time.Duration) how long the exporter should look back for closed pullrequests"      default:"168h"`
 		}
 
 		Server struct {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"regexp"
 	"runtime"
 	"strings"
 
@@ -30,6 +31,8 @@ var (
 	AzureDevopsClient           *AzureDevops.AzureDevopsClient
 	AzureDevopsServiceDiscovery *azureDevopsServiceDiscovery
 
+	AnalyticsConfig config.AnalyticsConfig
+
 	// Git version information
 	gitCommit = "<unknown>"
 	gitTag    = "<unknown>"
@@ -105,6 +108,43 @@ func parseArguments() {
 		}
 	}
 
+	if Opts.AzureDevops.AgentPoolBillingDay < 1 || Opts.AzureDevops.AgentPoolBillingDay > 28 {
+		logger.Fatalf("invalid agentpool billing day \"%v\", should be between 1 and 28", Opts.AzureDevops.AgentPoolBillingDay)
+	}
+
+	// ensure branch patterns are valid regexps
+	for _, pattern := range Opts.AzureDevops.RepositoryBranchPattern {
+		if _, err := regexp.Compile(pattern); err != nil {
+			logger.Fatalf("invalid repository branch pattern \"%s\": %v", pattern, err)
+		}
+	}
+
+	// ensure build issue log patterns are valid regexps
+	for _, pattern := range Opts.AzureDevops.BuildIssueLogPattern {
+		if _, err := regexp.Compile(pattern); err != nil {
+			logger.Fatalf("invalid build issue log pattern \"%s\": %v", pattern, err)
+		}
+	}
+
+	// ensure dora patterns are valid regexps
+	for _, pattern := range append(Opts.Dora.Environment, Opts.Dora.Pipeline...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			logger.Fatalf("invalid dora pattern \"%s\": %v", pattern, err)
+		}
+	}
+
+	// ensure audit alerts are valid (alertName:actionIdPattern)
+	for _, alert := range Opts.Audit.Alert {
+		alertName, pattern, found := strings.Cut(alert, ":")
+		if !found || alertName == "" || pattern == "" {
+			logger.Fatalf("invalid audit alert \"%s\", should be 'alertName:actionIdPattern'", alert)
+		}
+
+		if _, err := regexp.Compile(pattern); err != nil {
+			logger.Fatalf("invalid audit alert pattern \"%s\": %v", pattern, err)
+		}
+	}
+
 	// use default scrape time if null
 	if Opts.Scrape.TimeProjects == nil {
 		Opts.Scrape.TimeProjects = &Opts.Scrape.Time
@@ -146,6 +186,72 @@ func parseArguments() {
 		Opts.Scrape.TimeQuery = &Opts.Scrape.Time
 	}
 
+	if Opts.Scrape.TimeAnalytics == nil {
+		Opts.Scrape.TimeAnalytics = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimePolicy == nil {
+		Opts.Scrape.TimePolicy = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeDeploymentGroup == nil {
+		Opts.Scrape.TimeDeploymentGroup = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimePipeline == nil {
+		Opts.Scrape.TimePipeline = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeDora == nil {
+		Opts.Scrape.TimeDora = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeServiceEndpoint == nil {
+		Opts.Scrape.TimeServiceEndpoint = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeToken == nil {
+		Opts.Scrape.TimeToken = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeLibrary == nil {
+		Opts.Scrape.TimeLibrary = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeAudit == nil {
+		Opts.Scrape.TimeAudit = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeUserEntitlement == nil {
+		Opts.Scrape.TimeUserEntitlement = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeFeed == nil {
+		Opts.Scrape.TimeFeed = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeAdvSecurity == nil {
+		Opts.Scrape.TimeAdvSecurity = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeTestPlan == nil {
+		Opts.Scrape.TimeTestPlan = &Opts.Scrape.Time
+	}
+
+	if Opts.Scrape.TimeTeam == nil {
+		Opts.Scrape.TimeTeam = &Opts.Scrape.Time
+	}
+
+	// load analytics query config
+	if Opts.Analytics.Config != "" {
+		logger.Infof("reading analytics config from file \"%s\"", Opts.Analytics.Config)
+		if val, err := config.LoadAnalyticsConfig(Opts.Analytics.Config); err == nil {
+			AnalyticsConfig = val
+		} else {
+			logger.Fatalf("unable to read analytics config file \"%s\": %v", Opts.Analytics.Config, err)
+		}
+	}
+
 	if v := os.Getenv("AZURE_DEVOPS_FILTER_AGENTPOOL"); v != "" {
 		logger.Fatal("deprecated env var AZURE_DEVOPS_FILTER_AGENTPOOL detected, please use AZURE_DEVOPS_AGENTPOOL")
 	}
@@ -190,6 +296,13 @@ func initAzureDevOpsConnection() {
 			logger.Fatalf(err.Error())
 		}
 	}
+
+	// service endpoint secret expiry (Microsoft Graph) is only available with azure authentication
+	if Opts.AzureDevops.ServiceEndpointSecretExpiry && !AzureDevopsClient.SupportsAzAuth() {
+		logger.Warn("service endpoint secret expiry requires azure authentication instead of an access token, skipping Microsoft Graph lookups")
+		Opts.AzureDevops.ServiceEndpointSecretExpiry = false
+	}
+
 	AzureDevopsClient.SetApiVersion(Opts.AzureDevops.ApiVersion)
 	AzureDevopsClient.SetConcurrency(Opts.Request.ConcurrencyLimit)
 	AzureDevopsClient.SetRetries(Opts.Request.Retries)
@@ -202,6 +315,7 @@ func initAzureDevOpsConnection() {
 	AzureDevopsClient.LimitDeploymentPerDefinition = Opts.Limit.DeploymentPerDefinition
 	AzureDevopsClient.LimitReleaseDefinitionsPerProject = Opts.Limit.ReleaseDefinitionsPerProject
 	AzureDevopsClient.LimitReleasesPerProject = Opts.Limit.ReleasesPerProject
+	AzureDevopsClient.LimitPullRequestsPerRepository = Opts.Limit.PullRequestsPerRepository
 }
 
 func initMetricCollector() {
@@ -231,6 +345,18 @@ func initMetricCollector() {
 		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
 	}
 
+	collectorName = "AgentPoolElastic"
+	if Opts.Scrape.TimeElasticPool.Seconds() > 0 {
+		c := collector.New(collectorName, &MetricsCollectorAgentPoolElastic{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeElasticPool)
+		c.SetCache(Opts.GetCachePath("agentpoolelastic.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
 	collectorName = "LatestBuild"
 	if Opts.Scrape.TimeLive.Seconds() > 0 {
 		c := collector.New(collectorName, &MetricsCollectorLatestBuild{}, logger)
@@ -338,6 +464,174 @@ func initMetricCollector() {
 	} else {
 		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
 	}
+
+	collectorName = "Analytics"
+	if Opts.Scrape.TimeAnalytics.Seconds() > 0 && len(AnalyticsConfig.Queries) > 0 {
+		c := collector.New(collectorName, &MetricsCollectorAnalytics{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeAnalytics)
+		c.SetCache(Opts.GetCachePath("analytics.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops, AnalyticsConfig))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Policy"
+	if Opts.Scrape.TimePolicy.Seconds() > 0 && Opts.AzureDevops.PolicyEnabled {
+		c := collector.New(collectorName, &MetricsCollectorPolicy{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimePolicy)
+		c.SetCache(Opts.GetCachePath("policy.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "DeploymentGroup"
+	if Opts.Scrape.TimeDeploymentGroup.Seconds() > 0 && Opts.AzureDevops.DeploymentGroupEnabled {
+		c := collector.New(collectorName, &MetricsCollectorDeploymentGroup{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeDeploymentGroup)
+		c.SetCache(Opts.GetCachePath("deploymentgroup.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Pipeline"
+	if Opts.Scrape.TimePipeline.Seconds() > 0 && Opts.AzureDevops.PipelineEnabled {
+		c := collector.New(collectorName, &MetricsCollectorPipeline{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimePipeline)
+		c.SetCache(Opts.GetCachePath("pipeline.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Dora"
+	if Opts.Scrape.TimeDora.Seconds() > 0 && Opts.Dora.Enabled {
+		c := collector.New(collectorName, &MetricsCollectorDora{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeDora)
+		c.SetCache(Opts.GetCachePath("dora.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops, Opts.Dora))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "ServiceEndpoint"
+	if Opts.Scrape.TimeServiceEndpoint.Seconds() > 0 && Opts.AzureDevops.ServiceEndpointEnabled {
+		c := collector.New(collectorName, &MetricsCollectorServiceEndpoint{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeServiceEndpoint)
+		c.SetCache(Opts.GetCachePath("serviceendpoint.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Token"
+	if Opts.Scrape.TimeToken.Seconds() > 0 && Opts.AzureDevops.FetchTokenExpiry {
+		c := collector.New(collectorName, &MetricsCollectorToken{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeToken)
+		c.SetCache(Opts.GetCachePath("token.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Library"
+	if Opts.Scrape.TimeLibrary.Seconds() > 0 && Opts.AzureDevops.LibraryEnabled {
+		c := collector.New(collectorName, &MetricsCollectorLibrary{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeLibrary)
+		c.SetCache(Opts.GetCachePath("library.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Audit"
+	if Opts.Scrape.TimeAudit.Seconds() > 0 && Opts.Audit.Enabled {
+		c := collector.New(collectorName, &MetricsCollectorAudit{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeAudit)
+		c.SetCache(Opts.GetCachePath("audit.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops, Opts.Audit))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "UserEntitlement"
+	if Opts.Scrape.TimeUserEntitlement.Seconds() > 0 && Opts.AzureDevops.UserEntitlementEnabled {
+		c := collector.New(collectorName, &MetricsCollectorUserEntitlement{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeUserEntitlement)
+		c.SetCache(Opts.GetCachePath("userentitlement.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Feed"
+	if Opts.Scrape.TimeFeed.Seconds() > 0 && Opts.AzureDevops.FeedEnabled {
+		c := collector.New(collectorName, &MetricsCollectorFeed{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeFeed)
+		c.SetCache(Opts.GetCachePath("feed.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "AdvancedSecurity"
+	if Opts.Scrape.TimeAdvSecurity.Seconds() > 0 && Opts.AzureDevops.AdvSecurityEnabled {
+		c := collector.New(collectorName, &MetricsCollectorAdvancedSecurity{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeAdvSecurity)
+		c.SetCache(Opts.GetCachePath("advancedsecurity.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "TestPlan"
+	if Opts.Scrape.TimeTestPlan.Seconds() > 0 && Opts.AzureDevops.TestPlanEnabled {
+		c := collector.New(collectorName, &MetricsCollectorTestPlan{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeTestPlan)
+		c.SetCache(Opts.GetCachePath("testplan.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
+
+	collectorName = "Team"
+	if Opts.Scrape.TimeTeam.Seconds() > 0 && Opts.AzureDevops.TeamEnabled {
+		c := collector.New(collectorName, &MetricsCollectorTeam{}, logger)
+		c.SetScapeTime(*Opts.Scrape.TimeTeam)
+		c.SetCache(Opts.GetCachePath("team.json"), collector.BuildCacheTag(cacheTag, Opts.AzureDevops))
+		if err := c.Start(); err != nil {
+			logger.Fatal(err.Error())
+		}
+	} else {
+		logger.With(zap.String("collector", collectorName)).Info("collector disabled")
+	}
 }
 
 // start and handle prometheus handler
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorAdvancedSecurity struct {
+	collector.Processor
+
+	prometheus struct {
+		advancedSecurityEnabled *prometheus.GaugeVec
+		advancedSecurityAlerts  *prometheus.GaugeVec
+		advancedSecurityFixTime *prometheus.HistogramVec
+	}
+}
+
+// advancedSecurityAlertKey is the aggregation key of open alerts of a repository
+type advancedSecurityAlertKey struct {
+	AlertType string
+	Severity  string
+	RuleId    string
+	RuleName  string
+	Age       string
+}
+
+func (m *MetricsCollectorAdvancedSecurity) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.advancedSecurityEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_advancedsecurity_enabled",
+			Help: "Azure DevOps advanced security enablement of repository",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+		},
+	)
+	m.Collector.RegisterMetricList("advancedSecurityEnabled", m.prometheus.advancedSecurityEnabled, true)
+
+	m.prometheus.advancedSecurityAlerts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_advancedsecurity_alerts",
+			Help: "Azure DevOps advanced security number of open alerts (code, secret and dependency scanning) per age (7d, 30d, 90d, older)",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"alertType",
+			"severity",
+			"ruleID",
+			"ruleName",
+			"age",
+		},
+	)
+	m.Collector.RegisterMetricList("advancedSecurityAlerts", m.prometheus.advancedSecurityAlerts, true)
+
+	m.prometheus.advancedSecurityFixTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_advancedsecurity_alert_fix_time",
+			Help: "Azure DevOps advanced security time to fix alerts (seconds from first seen to fixed)",
+			Buckets: []float64{
+				1 * 24 * 60 * 60,   // 1d
+				3 * 24 * 60 * 60,   // 3d
+				7 * 24 * 60 * 60,   // 7d
+				14 * 24 * 60 * 60,  // 14d
+				30 * 24 * 60 * 60,  // 30d
+				60 * 24 * 60 * 60,  // 60d
+				90 * 24 * 60 * 60,  // 90d
+				180 * 24 * 60 * 60, // 180d
+				365 * 24 * 60 * 60, // 365d
+			},
+		},
+		[]string{
+			"projectID",
+			"alertType",
+			"severity",
+		},
+	)
+	m.Collector.RegisterMetricList("advancedSecurityFixTime", m.prometheus.advancedSecurityFixTime, false)
+}
+
+func (m *MetricsCollectorAdvancedSecurity) Reset() {}
+
+func (m *MetricsCollectorAdvancedSecurity) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+
+		for _, repository := range project.RepositoryList.List {
+			if repository.Disabled() {
+				continue
+			}
+
+			repositoryLogger := projectLogger.With(zap.String("repository", repository.Name))
+			m.collectAlerts(ctx, repositoryLogger, callback, project, repository)
+		}
+	}
+}
+
+func (m *MetricsCollectorAdvancedSecurity) collectAlerts(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, repository devopsClient.Repository) {
+	advancedSecurityEnabledMetric := m.Collector.GetMetricList("advancedSecurityEnabled")
+	advancedSecurityAlertsMetric := m.Collector.GetMetricList("advancedSecurityAlerts")
+	advancedSecurityFixTimeMetric := m.Collector.GetMetricList("advancedSecurityFixTime")
+
+	// alerts are only available for repositories with enabled advanced security
+	enablement, err := AzureDevopsClient.GetAdvancedSecurityEnablement(project.Id, repository.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	advancedSecurityEnabledMetric.AddBool(prometheus.Labels{
+		"projectID":    project.Id,
+		"repositoryID": repository.Id,
+	}, enablement.AdvSecEnabled)
+
+	if !enablement.AdvSecEnabled {
+		return
+	}
+
+	list, err := AzureDevopsClient.ListAdvancedSecurityAlerts(project.Id, repository.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	// fix times are only observed once (for alerts fixed since last run)
+	lastScrapeTime := time.Now().Add(-*m.Collector.GetScapeTime())
+	if val := m.Collector.GetLastScapeTime(); val != nil {
+		lastScrapeTime = *val
+	}
+
+	now := time.Now()
+	openAlerts := map[advancedSecurityAlertKey]int{}
+	for _, alert := range list.List {
+		if alert.IsOpen() {
+			age := "unknown"
+			if alert.FirstSeenDate != nil {
+				age = ageBucket(now.Sub(*alert.FirstSeenDate))
+			}
+
+			openAlerts[advancedSecurityAlertKey{
+				AlertType: alert.AlertType,
+				Severity:  alert.Severity,
+				RuleId:    alert.Rule.Id,
+				RuleName:  alert.RuleName(),
+				Age:       age,
+			}]++
+			continue
+		}
+
+		if fixDuration := alert.FixDuration(); fixDuration != nil && alert.FixedDate.After(lastScrapeTime) {
+			advancedSecurityFixTimeMetric.AddDuration(prometheus.Labels{
+				"projectID": project.Id,
+				"alertType": alert.AlertType,
+				"severity":  alert.Severity,
+			}, *fixDuration)
+		}
+	}
+
+	for key, count := range openAlerts {
+		advancedSecurityAlertsMetric.Add(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"alertType":    key.AlertType,
+			"severity":     key.Severity,
+			"ruleID":       key.RuleId,
+			"ruleName":     key.RuleName,
+			"age":          key.Age,
+		}, float64(count))
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/webdevops/go-common/prometheus/collector"
@@ -15,19 +17,107 @@ type MetricsCollectorAgentPool struct {
 	collector.Processor
 
 	prometheus struct {
-		agentPool            *prometheus.GaugeVec
-		agentPoolSize        *prometheus.GaugeVec
-		agentPoolUsage       *prometheus.GaugeVec
-		agentPoolAgent       *prometheus.GaugeVec
-		agentPoolAgentStatus *prometheus.GaugeVec
-		agentPoolAgentJob    *prometheus.GaugeVec
-		agentPoolQueueLength *prometheus.GaugeVec
+		agentPool                *prometheus.GaugeVec
+		agentPoolSize            *prometheus.GaugeVec
+		agentPoolUsage           *prometheus.GaugeVec
+		agentPoolAgent           *prometheus.GaugeVec
+		agentPoolAgentStatus     *prometheus.GaugeVec
+		agentPoolAgentJob        *prometheus.GaugeVec
+		agentPoolAgentCapability *prometheus.GaugeVec
+		agentPoolAgentOutdated   *prometheus.GaugeVec
+		agentPoolQueueLength     *prometheus.GaugeVec
+		agentPoolQueueOldest     *prometheus.GaugeVec
+		agentPoolJobDemand       *prometheus.GaugeVec
+		agentPoolJobWait         *prometheus.HistogramVec
+		agentPoolJobDuration     *prometheus.HistogramVec
+		agentPoolJobMinutes      *prometheus.CounterVec
 	}
+
+	// finished job cursor per agent pool, persisted in the cache path (if set) so job minutes are not counted twice or missed after restarts
+	jobCursor      map[int64]agentPoolJobCursor
+	jobCursorStore *cacheStore
+
+	// agent pools without cursor start at the oldest listed job if the cursor can be persisted,
+	// otherwise at the last scrape time as jobs would be counted again after each restart
+	jobCursorFromOldest bool
+}
+
+// agentPoolJobCursor is the position of the latest processed finished job of an agent pool,
+// the ids of the jobs finished at this timestamp are kept for deduplication
+type agentPoolJobCursor struct {
+	FinishTime *time.Time `json:"finishTime"`
+	RequestIds []int64    `json:"requestIds"`
+}
+
+// advance returns the jobs finished after the cursor (all finished jobs if the cursor has no position yet)
+// and the cursor moved to the latest of them
+func (c agentPoolJobCursor) advance(jobList []devopsClient.JobRequest) (finishedJobs []devopsClient.JobRequest, next agentPoolJobCursor) {
+	processedJobs := map[int64]bool{}
+	for _, requestId := range c.RequestIds {
+		processedJobs[requestId] = true
+	}
+
+	next = agentPoolJobCursor{
+		FinishTime: c.FinishTime,
+		RequestIds: append([]int64{}, c.RequestIds...),
+	}
+
+	for _, job := range jobList {
+		if job.FinishTime == nil {
+			continue
+		}
+
+		finishTime := *job.FinishTime
+		if c.FinishTime != nil && (finishTime.Before(*c.FinishTime) || (finishTime.Equal(*c.FinishTime) && processedJobs[job.RequestId])) {
+			continue
+		}
+		finishedJobs = append(finishedJobs, job)
+
+		switch {
+		case next.FinishTime == nil || finishTime.After(*next.FinishTime):
+			next = agentPoolJobCursor{
+				FinishTime: &finishTime,
+				RequestIds: []int64{job.RequestId},
+			}
+		case finishTime.Equal(*next.FinishTime):
+			next.RequestIds = append(next.RequestIds, job.RequestId)
+		}
+	}
+
+	return
+}
+
+// equal checks if both cursors are at the same position
+func (c agentPoolJobCursor) equal(other agentPoolJobCursor) bool {
+	if c.FinishTime == nil || other.FinishTime == nil {
+		return c.FinishTime == other.FinishTime
+	}
+	return c.FinishTime.Equal(*other.FinishTime) && len(c.RequestIds) == len(other.RequestIds)
 }
 
 func (m *MetricsCollectorAgentPool) Setup(collector *collector.Collector) {
 	m.Processor.Setup(collector)
 
+	m.jobCursor = map[int64]agentPoolJobCursor{}
+
+	jobCursorStore, err := newCacheStore(Opts.GetCachePath("agentpooljobcursor.json"))
+	if err != nil {
+		m.Logger().Fatal(err)
+	}
+	m.jobCursorStore = jobCursorStore
+
+	if m.jobCursorStore != nil {
+		if exists, err := m.jobCursorStore.Read(m.Context(), &m.jobCursor); err != nil {
+			m.Logger().Warnf("unable to restore agentpool job cursor, starting from scrape time: %v", err)
+			m.jobCursor = map[int64]agentPoolJobCursor{}
+		} else {
+			m.jobCursorFromOldest = true
+			if exists {
+				m.Logger().Infof("restored agentpool job cursor for %v agent pools", len(m.jobCursor))
+			}
+		}
+	}
+
 	m.prometheus.agentPool = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_agentpool_info",
@@ -113,6 +203,34 @@ func (m *MetricsCollectorAgentPool) Setup(collector *collector.Collector) {
 	)
 	m.Collector.RegisterMetricList("agentPoolAgentJob", m.prometheus.agentPoolAgentJob, true)
 
+	m.prometheus.agentPoolAgentCapability = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_agent_capability",
+			Help: "Azure DevOps agentpool agent capabilities",
+		},
+		[]string{
+			"agentPoolAgentID",
+			"name",
+			"source",
+			"type",
+			"info",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolAgentCapability", m.prometheus.agentPoolAgentCapability, true)
+
+	m.prometheus.agentPoolAgentOutdated = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_agent_outdated",
+			Help: "Azure DevOps agentpool agent version is older than the latest agent release",
+		},
+		[]string{
+			"agentPoolAgentID",
+			"agentPoolAgentVersion",
+			"latestVersion",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolAgentOutdated", m.prometheus.agentPoolAgentOutdated, true)
+
 	m.prometheus.agentPoolQueueLength = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_agentpool_queue_length",
@@ -123,6 +241,91 @@ func (m *MetricsCollectorAgentPool) Setup(collector *collector.Collector) {
 		},
 	)
 	m.Collector.RegisterMetricList("agentPoolQueueLength", m.prometheus.agentPoolQueueLength, true)
+
+	m.prometheus.agentPoolQueueOldest = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_queue_oldest",
+			Help: "Azure DevOps agentpool age (seconds) of the oldest waiting job",
+		},
+		[]string{
+			"agentPoolID",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolQueueOldest", m.prometheus.agentPoolQueueOldest, true)
+
+	m.prometheus.agentPoolJobDemand = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_job_demand",
+			Help: "Azure DevOps agentpool number of waiting and running jobs by demand",
+		},
+		[]string{
+			"agentPoolID",
+			"demand",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolJobDemand", m.prometheus.agentPoolJobDemand, true)
+
+	m.prometheus.agentPoolJobWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_agentpool_job_wait",
+			Help: "Azure DevOps agentpool job wait time (seconds from queued to assigned)",
+			Buckets: []float64{
+				5,           // 5s
+				15,          // 15s
+				30,          // 30s
+				60,          // 1m
+				2 * 60,      // 2m
+				5 * 60,      // 5m
+				10 * 60,     // 10m
+				30 * 60,     // 30m
+				60 * 60,     // 1h
+				2 * 60 * 60, // 2h
+			},
+		},
+		[]string{
+			"agentPoolID",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolJobWait", m.prometheus.agentPoolJobWait, false)
+
+	m.prometheus.agentPoolJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_agentpool_job_duration",
+			Help: "Azure DevOps agentpool job execution time (seconds from assigned to finished)",
+			Buckets: []float64{
+				30,          // 30s
+				60,          // 1m
+				2 * 60,      // 2m
+				5 * 60,      // 5m
+				10 * 60,     // 10m
+				20 * 60,     // 20m
+				30 * 60,     // 30m
+				60 * 60,     // 1h
+				2 * 60 * 60, // 2h
+				4 * 60 * 60, // 4h
+			},
+		},
+		[]string{
+			"agentPoolID",
+			"result",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolJobDuration", m.prometheus.agentPoolJobDuration, false)
+
+	m.prometheus.agentPoolJobMinutes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azure_devops_agentpool_job_minutes",
+			Help: "Azure DevOps agentpool job execution minutes per project and billing period (finished jobs, counted once using a job cursor persisted in cache.path, without cache.path only jobs finished after startup are counted)",
+		},
+		[]string{
+			"agentPoolID",
+			"projectID",
+			"isHosted",
+			"billingPeriod",
+		},
+	)
+	m.Collector.RegisterMetricList("agentPoolJobMinutes", m.prometheus.agentPoolJobMinutes, false)
 }
 
 func (m *MetricsCollectorAgentPool) Reset() {}
@@ -136,10 +339,36 @@ func (m *MetricsCollectorAgentPool) Collect(callback chan<- func()) {
 		m.collectAgentInfo(ctx, projectLogger, callback, project)
 	}
 
+	var latestAgentVersion *devopsClient.AgentPackageVersion
+	if agentPackageList, err := AzureDevopsClient.ListAgentPackages(); err == nil {
+		latestAgentVersion = agentPackageList.LatestVersion()
+	} else {
+		logger.Error(err)
+	}
+
+	// hosted (microsoft) and self-hosted pools for job minute consumption
+	agentPoolHosted := map[int64]bool{}
+	if agentPoolList, err := AzureDevopsClient.ListAgentPools(); err == nil {
+		for _, agentPool := range agentPoolList.Value {
+			agentPoolHosted[agentPool.ID] = agentPool.IsHosted
+		}
+	} else {
+		logger.Error(err)
+	}
+
+	jobCursorChanged := false
 	for _, agentPoolId := range AzureDevopsServiceDiscovery.AgentPoolList() {
 		agentPoolLogger := logger.With(zap.Int64("agentPoolId", agentPoolId))
-		m.collectAgentQueues(ctx, agentPoolLogger, callback, agentPoolId)
-		m.collectAgentPoolJobs(ctx, agentPoolLogger, callback, agentPoolId)
+		m.collectAgentQueues(ctx, agentPoolLogger, callback, agentPoolId, latestAgentVersion)
+		if m.collectAgentPoolJobs(ctx, agentPoolLogger, callback, agentPoolId, agentPoolHosted[agentPoolId]) {
+			jobCursorChanged = true
+		}
+	}
+
+	if jobCursorChanged && m.jobCursorStore != nil {
+		if err := m.jobCursorStore.Write(ctx, m.jobCursor); err != nil {
+			logger.With(zap.String("cache", m.jobCursorStore.raw)).Errorf("unable to persist agentpool job cursor: %v", err)
+		}
 	}
 }
 
@@ -167,7 +396,7 @@ func (m *MetricsCollectorAgentPool) collectAgentInfo(ctx context.Context, logger
 	}
 }
 
-func (m *MetricsCollectorAgentPool) collectAgentQueues(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), agentPoolId int64) {
+func (m *MetricsCollectorAgentPool) collectAgentQueues(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), agentPoolId int64, latestAgentVersion *devopsClient.AgentPackageVersion) {
 	list, err := AzureDevopsClient.ListAgentPoolAgents(agentPoolId)
 	if err != nil {
 		logger.Error(err)
@@ -178,6 +407,8 @@ func (m *MetricsCollectorAgentPool) collectAgentQueues(ctx context.Context, logg
 	agentPoolAgentMetric := m.Collector.GetMetricList("agentPoolAgent")
 	agentPoolAgentStatusMetric := m.Collector.GetMetricList("agentPoolAgentStatus")
 	agentPoolAgentJobMetric := m.Collector.GetMetricList("agentPoolAgentJob")
+	agentPoolAgentCapabilityMetric := m.Collector.GetMetricList("agentPoolAgentCapability")
+	agentPoolAgentOutdatedMetric := m.Collector.GetMetricList("agentPoolAgentOutdated")
 
 	agentPoolSize := 0
 	agentPoolUsed := 0
@@ -211,6 +442,42 @@ func (m *MetricsCollectorAgentPool) collectAgentQueues(ctx context.Context, logg
 		}
 		agentPoolAgentStatusMetric.Add(statusCreatedLabels, timeToFloat64(agentPoolAgent.CreatedOn))
 
+		if latestAgentVersion != nil && agentPoolAgent.Version != "" {
+			agentPoolAgentOutdatedMetric.AddBool(prometheus.Labels{
+				"agentPoolAgentID":      int64ToString(agentPoolAgent.Id),
+				"agentPoolAgentVersion": agentPoolAgent.Version,
+				"latestVersion":         latestAgentVersion.String(),
+			}, devopsClient.ParseAgentPackageVersion(agentPoolAgent.Version).Less(*latestAgentVersion))
+		}
+
+		if Opts.AzureDevops.AgentPoolCapabilitySchema != nil {
+			capabilityList, err := agentPoolAgent.ParseCapabilities(*Opts.AzureDevops.AgentPoolCapabilitySchema)
+			if err != nil {
+				logger.Error(err)
+			}
+
+			for _, capability := range capabilityList {
+				capabilityLabels := prometheus.Labels{
+					"agentPoolAgentID": int64ToString(agentPoolAgent.Id),
+					"name":             capability.Name,
+					"source":           capability.Source,
+					"type":             capability.Type,
+					"info":             "",
+				}
+
+				switch capability.Type {
+				case "number":
+					agentPoolAgentCapabilityMetric.AddIfNotNil(capabilityLabels, capability.NumberValue())
+				case "bool":
+					value, _ := strconv.ParseBool(capability.Value)
+					agentPoolAgentCapabilityMetric.AddBool(capabilityLabels, value)
+				case "info":
+					capabilityLabels["info"] = capability.Value
+					agentPoolAgentCapabilityMetric.AddInfo(capabilityLabels)
+				}
+			}
+		}
+
 		if agentPoolAgent.AssignedRequest.RequestId > 0 {
 			agentPoolUsed++
 			jobLabels := prometheus.Labels{
@@ -234,7 +501,8 @@ func (m *MetricsCollectorAgentPool) collectAgentQueues(ctx context.Context, logg
 	}, usage)
 }
 
-func (m *MetricsCollectorAgentPool) collectAgentPoolJobs(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), agentPoolId int64) {
+// collectAgentPoolJobs exports queue, wait and execution metrics of agent pool jobs, returns true if the finished job cursor was advanced
+func (m *MetricsCollectorAgentPool) collectAgentPoolJobs(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), agentPoolId int64, isHosted bool) (cursorChanged bool) {
 	list, err := AzureDevopsClient.ListAgentPoolJobs(agentPoolId)
 	if err != nil {
 		logger.Error(err)
@@ -242,12 +510,81 @@ func (m *MetricsCollectorAgentPool) collectAgentPoolJobs(ctx context.Context, lo
 	}
 
 	agentPoolQueueLengthMetric := m.Collector.GetMetricList("agentPoolQueueLength")
+	agentPoolQueueOldestMetric := m.Collector.GetMetricList("agentPoolQueueOldest")
+	agentPoolJobDemandMetric := m.Collector.GetMetricList("agentPoolJobDemand")
+	agentPoolJobWaitMetric := m.Collector.GetMetricList("agentPoolJobWait")
+	agentPoolJobDurationMetric := m.Collector.GetMetricList("agentPoolJobDuration")
+	agentPoolJobMinutesMetric := m.Collector.GetMetricList("agentPoolJobMinutes")
+
+	// wait times are only observed once (for jobs assigned since last run)
+	lastScrapeTime := time.Now().Add(-*m.Collector.GetScapeTime())
+	if val := m.Collector.GetLastScapeTime(); val != nil {
+		lastScrapeTime = *val
+	}
+
+	// execution times and job minutes are only observed once (for jobs finished after the cursor)
+	cursor, exists := m.jobCursor[agentPoolId]
+	if !exists && !m.jobCursorFromOldest {
+		cursor = agentPoolJobCursor{FinishTime: &lastScrapeTime}
+	}
 
 	notStartedJobCount := 0
+	var oldestQueueTime *time.Time
+	demandCount := map[string]map[string]int64{
+		"waiting": {},
+		"running": {},
+	}
 
 	for _, agentPoolJob := range list.List {
 		if agentPoolJob.AssignTime == nil {
+			// jobs cancelled before assignment are finished but were never assigned
+			if agentPoolJob.FinishTime != nil {
+				continue
+			}
+
 			notStartedJobCount++
+
+			if oldestQueueTime == nil || agentPoolJob.QueueTime.Before(*oldestQueueTime) {
+				queueTime := agentPoolJob.QueueTime
+				oldestQueueTime = &queueTime
+			}
+
+			for _, demand := range agentPoolJob.Demands {
+				demandCount["waiting"][demand]++
+			}
+			continue
+		}
+
+		if agentPoolJob.FinishTime == nil {
+			for _, demand := range agentPoolJob.Demands {
+				demandCount["running"][demand]++
+			}
+		}
+
+		if agentPoolJob.AssignTime.After(lastScrapeTime) {
+			if waitDuration := agentPoolJob.WaitDuration(); waitDuration != nil {
+				agentPoolJobWaitMetric.AddDuration(prometheus.Labels{
+					"agentPoolID": int64ToString(agentPoolId),
+				}, *waitDuration)
+			}
+		}
+	}
+
+	finishedJobs, nextCursor := cursor.advance(list.List)
+	for _, agentPoolJob := range finishedJobs {
+		if executionDuration := agentPoolJob.ExecutionDuration(); executionDuration != nil {
+			agentPoolJobDurationMetric.AddDuration(prometheus.Labels{
+				"agentPoolID": int64ToString(agentPoolId),
+				"result":      agentPoolJob.Result,
+			}, *executionDuration)
+
+			// job minutes are accounted to the billing period the job finished in
+			agentPoolJobMinutesMetric.Add(prometheus.Labels{
+				"agentPoolID":   int64ToString(agentPoolId),
+				"projectID":     agentPoolJob.ScopeId,
+				"isHosted":      to.BoolString(isHosted),
+				"billingPeriod": billingPeriod(*agentPoolJob.FinishTime, Opts.AzureDevops.AgentPoolBillingDay),
+			}, executionDuration.Minutes())
 		}
 	}
 
@@ -256,4 +593,27 @@ func (m *MetricsCollectorAgentPool) collectAgentPoolJobs(ctx context.Context, lo
 	}
 
 	agentPoolQueueLengthMetric.Add(infoLabels, float64(notStartedJobCount))
+
+	oldestQueueAge := float64(0)
+	if oldestQueueTime != nil {
+		oldestQueueAge = time.Since(*oldestQueueTime).Seconds()
+	}
+	agentPoolQueueOldestMetric.Add(infoLabels, oldestQueueAge)
+
+	for status, demandList := range demandCount {
+		for demand, count := range demandList {
+			agentPoolJobDemandMetric.Add(prometheus.Labels{
+				"agentPoolID": int64ToString(agentPoolId),
+				"demand":      demand,
+				"status":      status,
+			}, float64(count))
+		}
+	}
+
+	if !exists || !nextCursor.equal(cursor) {
+		m.jobCursor[agentPoolId] = nextCursor
+		cursorChanged = true
+	}
+
+	return
 }
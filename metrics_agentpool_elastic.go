@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+const (
+	ElasticPoolLogLimit = 100
+)
+
+type MetricsCollectorAgentPoolElastic struct {
+	collector.Processor
+
+	prometheus struct {
+		elasticPool         *prometheus.GaugeVec
+		elasticPoolCapacity *prometheus.GaugeVec
+		elasticPoolNodes    *prometheus.GaugeVec
+		elasticPoolLog      *prometheus.GaugeVec
+		elasticPoolLogCount *prometheus.CounterVec
+	}
+}
+
+func (m *MetricsCollectorAgentPoolElastic) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.elasticPool = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_elastic_info",
+			Help: "Azure DevOps elastic (scale set) agentpool",
+		},
+		[]string{
+			"agentPoolID",
+			"serviceEndpointID",
+			"azureID",
+			"osType",
+			"state",
+			"recycleAfterEachUse",
+			"agentInteractiveUI",
+		},
+	)
+	m.Collector.RegisterMetricList("elasticPool", m.prometheus.elasticPool, true)
+
+	m.prometheus.elasticPoolCapacity = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_elastic_capacity",
+			Help: "Azure DevOps elastic (scale set) agentpool capacity (desired and current idle agents, size, max capacity)",
+		},
+		[]string{
+			"agentPoolID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("elasticPoolCapacity", m.prometheus.elasticPoolCapacity, true)
+
+	m.prometheus.elasticPoolNodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_elastic_nodes",
+			Help: "Azure DevOps elastic (scale set) agentpool number of nodes per state",
+		},
+		[]string{
+			"agentPoolID",
+			"state",
+			"agentState",
+			"computeState",
+		},
+	)
+	m.Collector.RegisterMetricList("elasticPoolNodes", m.prometheus.elasticPoolNodes, true)
+
+	m.prometheus.elasticPoolLog = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_agentpool_elastic_log",
+			Help: "Azure DevOps elastic (scale set) agentpool latest log entry (timestamp) per level and operation",
+		},
+		[]string{
+			"agentPoolID",
+			"level",
+			"operation",
+			"message",
+		},
+	)
+	m.Collector.RegisterMetricList("elasticPoolLog", m.prometheus.elasticPoolLog, true)
+
+	m.prometheus.elasticPoolLogCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azure_devops_agentpool_elastic_log_count",
+			Help: "Azure DevOps elastic (scale set) agentpool log entries per level and operation",
+		},
+		[]string{
+			"agentPoolID",
+			"level",
+			"operation",
+		},
+	)
+	m.Collector.RegisterMetricList("elasticPoolLogCount", m.prometheus.elasticPoolLogCount, false)
+}
+
+func (m *MetricsCollectorAgentPoolElastic) Reset() {}
+
+func (m *MetricsCollectorAgentPoolElastic) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	// elastic pools are only collected for configured agent pools
+	agentPoolList := AzureDevopsServiceDiscovery.AgentPoolList()
+	if len(agentPoolList) == 0 {
+		return
+	}
+
+	list, err := AzureDevopsClient.ListElasticPools()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, elasticPool := range list.List {
+		if !arrayIntContains(agentPoolList, elasticPool.PoolId) {
+			continue
+		}
+
+		agentPoolLogger := logger.With(zap.Int64("agentPoolId", elasticPool.PoolId))
+		m.collectElasticPool(ctx, agentPoolLogger, callback, elasticPool)
+	}
+}
+
+func (m *MetricsCollectorAgentPoolElastic) collectElasticPool(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), elasticPool devopsClient.ElasticPool) {
+	elasticPoolMetric := m.Collector.GetMetricList("elasticPool")
+	elasticPoolCapacityMetric := m.Collector.GetMetricList("elasticPoolCapacity")
+	elasticPoolNodesMetric := m.Collector.GetMetricList("elasticPoolNodes")
+	elasticPoolLogMetric := m.Collector.GetMetricList("elasticPoolLog")
+	elasticPoolLogCountMetric := m.Collector.GetMetricList("elasticPoolLogCount")
+
+	agentPoolId := int64ToString(elasticPool.PoolId)
+
+	elasticPoolMetric.AddInfo(prometheus.Labels{
+		"agentPoolID":         agentPoolId,
+		"serviceEndpointID":   elasticPool.ServiceEndpointId,
+		"azureID":             elasticPool.AzureId,
+		"osType":              elasticPool.OsType,
+		"state":               elasticPool.State,
+		"recycleAfterEachUse": to.BoolString(elasticPool.RecycleAfterEachUse),
+		"agentInteractiveUI":  to.BoolString(elasticPool.AgentInteractiveUI),
+	})
+
+	capacityLabels := func(capacityType string) prometheus.Labels {
+		return prometheus.Labels{
+			"agentPoolID": agentPoolId,
+			"type":        capacityType,
+		}
+	}
+
+	elasticPoolCapacityMetric.Add(capacityLabels("maxCapacity"), float64(elasticPool.MaxCapacity))
+	elasticPoolCapacityMetric.Add(capacityLabels("desiredIdle"), float64(elasticPool.DesiredIdle))
+	elasticPoolCapacityMetric.Add(capacityLabels("desiredSize"), float64(elasticPool.DesiredSize))
+	elasticPoolCapacityMetric.Add(capacityLabels("maxSavedNodeCount"), float64(elasticPool.MaxSavedNodeCount))
+	elasticPoolCapacityMetric.Add(capacityLabels("sizingAttempts"), float64(elasticPool.SizingAttempts))
+	elasticPoolCapacityMetric.AddDuration(capacityLabels("timeToLive"), time.Duration(elasticPool.TimeToLiveMinutes)*time.Minute)
+	if elasticPool.OfflineSince != nil {
+		elasticPoolCapacityMetric.AddTime(capacityLabels("offlineSince"), *elasticPool.OfflineSince)
+	}
+
+	// nodes
+	nodeList, err := AzureDevopsClient.ListElasticPoolNodes(elasticPool.PoolId)
+	if err == nil {
+		type nodeStateKey struct {
+			State        string
+			AgentState   string
+			ComputeState string
+		}
+
+		currentIdle := int64(0)
+		nodeStateCount := map[nodeStateKey]int64{}
+		for _, node := range nodeList.List {
+			nodeStateCount[nodeStateKey{
+				State:        node.State,
+				AgentState:   node.AgentState,
+				ComputeState: node.ComputeState,
+			}]++
+
+			if node.State == "idle" {
+				currentIdle++
+			}
+		}
+
+		elasticPoolCapacityMetric.Add(capacityLabels("currentIdle"), float64(currentIdle))
+		elasticPoolCapacityMetric.Add(capacityLabels("currentSize"), float64(len(nodeList.List)))
+
+		for nodeState, count := range nodeStateCount {
+			elasticPoolNodesMetric.Add(prometheus.Labels{
+				"agentPoolID":  agentPoolId,
+				"state":        nodeState.State,
+				"agentState":   nodeState.AgentState,
+				"computeState": nodeState.ComputeState,
+			}, float64(count))
+		}
+	} else {
+		logger.Error(err)
+	}
+
+	// logs
+	logList, err := AzureDevopsClient.ListElasticPoolLogs(elasticPool.PoolId, ElasticPoolLogLimit)
+	if err == nil {
+		// log entries are only counted once (for entries since last run)
+		lastScrapeTime := time.Now().Add(-*m.Collector.GetScapeTime())
+		if val := m.Collector.GetLastScapeTime(); val != nil {
+			lastScrapeTime = *val
+		}
+
+		type logKey struct {
+			Level     string
+			Operation string
+		}
+
+		latestLogEntry := map[logKey]devopsClient.ElasticPoolLog{}
+		for _, logEntry := range logList.List {
+			key := logKey{Level: logEntry.Level, Operation: logEntry.Operation}
+
+			if val, exists := latestLogEntry[key]; !exists || logEntry.Timestamp.After(val.Timestamp) {
+				latestLogEntry[key] = logEntry
+			}
+
+			if logEntry.Timestamp.After(lastScrapeTime) {
+				elasticPoolLogCountMetric.Add(prometheus.Labels{
+					"agentPoolID": agentPoolId,
+					"level":       logEntry.Level,
+					"operation":   logEntry.Operation,
+				}, 1)
+			}
+		}
+
+		for _, logEntry := range latestLogEntry {
+			elasticPoolLogMetric.AddTime(prometheus.Labels{
+				"agentPoolID": agentPoolId,
+				"level":       logEntry.Level,
+				"operation":   logEntry.Operation,
+				"message":     logEntry.Message,
+			}, logEntry.Timestamp)
+		}
+	} else {
+		logger.Error(err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+func TestAgentPoolJobCursorAdvance(t *testing.T) {
+	t1 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	job := func(requestId int64, finishTime *time.Time) devopsClient.JobRequest {
+		return devopsClient.JobRequest{RequestId: requestId, FinishTime: finishTime}
+	}
+
+	tests := []struct {
+		name           string
+		cursor         agentPoolJobCursor
+		jobs           []devopsClient.JobRequest
+		expectedJobs   []int64
+		expectedCursor agentPoolJobCursor
+	}{
+		{
+			name:           "no cursor, no finished jobs",
+			cursor:         agentPoolJobCursor{},
+			jobs:           []devopsClient.JobRequest{job(1, nil)},
+			expectedJobs:   nil,
+			expectedCursor: agentPoolJobCursor{RequestIds: []int64{}},
+		},
+		{
+			name:           "no cursor",
+			cursor:         agentPoolJobCursor{},
+			jobs:           []devopsClient.JobRequest{job(1, &t2), job(2, &t1), job(3, nil), job(4, &t2)},
+			expectedJobs:   []int64{1, 2, 4},
+			expectedCursor: agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1, 4}},
+		},
+		{
+			name:           "skip jobs finished before cursor",
+			cursor:         agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1}},
+			jobs:           []devopsClient.JobRequest{job(2, &t1), job(3, &t3)},
+			expectedJobs:   []int64{3},
+			expectedCursor: agentPoolJobCursor{FinishTime: &t3, RequestIds: []int64{3}},
+		},
+		{
+			name:           "skip processed jobs at cursor finish time",
+			cursor:         agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1}},
+			jobs:           []devopsClient.JobRequest{job(1, &t2), job(4, &t2)},
+			expectedJobs:   []int64{4},
+			expectedCursor: agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1, 4}},
+		},
+		{
+			name:           "no new jobs",
+			cursor:         agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1}},
+			jobs:           []devopsClient.JobRequest{job(1, &t2), job(5, nil)},
+			expectedJobs:   nil,
+			expectedCursor: agentPoolJobCursor{FinishTime: &t2, RequestIds: []int64{1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jobs, cursor := test.cursor.advance(test.jobs)
+
+			var requestIds []int64
+			for _, job := range jobs {
+				requestIds = append(requestIds, job.RequestId)
+			}
+			if !reflect.DeepEqual(requestIds, test.expectedJobs) {
+				t.Errorf("expected jobs %v, got %v", test.expectedJobs, requestIds)
+			}
+
+			if !reflect.DeepEqual(cursor, test.expectedCursor) {
+				t.Errorf("expected cursor %v, got %v", test.expectedCursor, cursor)
+			}
+
+			if changed := !cursor.equal(test.cursor); changed != (len(test.expectedJobs) > 0) {
+				t.Errorf("expected cursor changed %v, got %v", len(test.expectedJobs) > 0, changed)
+			}
+		})
+	}
+}
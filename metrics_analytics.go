@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-devops-exporter/config"
+)
+
+type MetricsCollectorAnalytics struct {
+	collector.Processor
+
+	prometheus struct {
+		query map[string]*prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorAnalytics) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.query = map[string]*prometheus.GaugeVec{}
+	for _, query := range AnalyticsConfig.Queries {
+		labels := []string{"projectID"}
+		for labelName := range query.Labels {
+			labels = append(labels, labelName)
+		}
+		sort.Strings(labels[1:])
+
+		m.prometheus.query[query.Metric] = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: query.Metric,
+				Help: query.Help,
+			},
+			labels,
+		)
+		m.Collector.RegisterMetricList(query.Metric, m.prometheus.query[query.Metric], true)
+	}
+}
+
+func (m *MetricsCollectorAnalytics) Reset() {}
+
+func (m *MetricsCollectorAnalytics) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, query := range AnalyticsConfig.Queries {
+		queryLogger := logger.With(zap.String("metric", query.Metric))
+
+		if query.Scope == config.AnalyticsScopeOrganization {
+			m.collectQuery(ctx, queryLogger, callback, query, "")
+			continue
+		}
+
+		for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+			if len(query.Projects) > 0 && !arrayStringContains(query.Projects, project.Id) && !arrayStringContains(query.Projects, project.Name) {
+				continue
+			}
+
+			projectLogger := queryLogger.With(zap.String("project", project.Name))
+			m.collectQuery(ctx, projectLogger, callback, query, project.Id)
+		}
+	}
+}
+
+func (m *MetricsCollectorAnalytics) collectQuery(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), query config.AnalyticsQuery, projectID string) {
+	list, err := AzureDevopsClient.QueryAnalytics(projectID, query.Query)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	queryMetric := m.Collector.GetMetricList(query.Metric)
+
+	for _, row := range list.List {
+		labels := prometheus.Labels{
+			"projectID": projectID,
+		}
+		for labelName, column := range query.Labels {
+			labels[labelName] = row.GetString(column)
+		}
+
+		queryMetric.AddIfNotNil(labels, row.GetFloat64(query.Value))
+	}
+}
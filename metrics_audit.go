@@ -0,0 +1,196 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorAudit struct {
+	collector.Processor
+
+	prometheus struct {
+		auditEvents *prometheus.CounterVec
+		auditAlert  *prometheus.CounterVec
+		auditCursor *prometheus.GaugeVec
+	}
+
+	alerts []auditAlert
+
+	// cursor is persisted in the cache path (if set) so events are not counted twice after restarts
+	cursor      auditCursor
+	cursorStore *cacheStore
+}
+
+// auditAlert is a sensitive action (actionId pattern) exported as alert metric
+type auditAlert struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// auditCursor is the position of the latest processed audit event,
+// the audit log startTime is inclusive so the ids of the events at this timestamp are kept for deduplication
+type auditCursor struct {
+	Timestamp *time.Time `json:"timestamp"`
+	EventIds  []string   `json:"eventIds"`
+}
+
+// advance returns the events after the cursor (all events if the cursor has no position yet)
+// and the cursor moved to the latest of them
+func (c auditCursor) advance(eventList []devopsClient.AuditLogEntry) (events []devopsClient.AuditLogEntry, next auditCursor) {
+	processedEvents := map[string]bool{}
+	for _, eventId := range c.EventIds {
+		processedEvents[eventId] = true
+	}
+
+	next = auditCursor{
+		Timestamp: c.Timestamp,
+		EventIds:  append([]string{}, c.EventIds...),
+	}
+
+	for _, event := range eventList {
+		// already processed in previous run
+		if c.Timestamp != nil {
+			if event.Timestamp.Before(*c.Timestamp) || (event.Timestamp.Equal(*c.Timestamp) && processedEvents[event.Id]) {
+				continue
+			}
+		}
+		events = append(events, event)
+
+		switch {
+		case next.Timestamp == nil || event.Timestamp.After(*next.Timestamp):
+			eventTime := event.Timestamp
+			next = auditCursor{
+				Timestamp: &eventTime,
+				EventIds:  []string{event.Id},
+			}
+		case event.Timestamp.Equal(*next.Timestamp):
+			next.EventIds = append(next.EventIds, event.Id)
+		}
+	}
+
+	return
+}
+
+func (m *MetricsCollectorAudit) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	for _, alert := range Opts.Audit.Alert {
+		alertName, pattern, _ := strings.Cut(alert, ":")
+		m.alerts = append(m.alerts, auditAlert{
+			Name:    alertName,
+			Pattern: regexp.MustCompile(pattern),
+		})
+	}
+
+	cursorStore, err := newCacheStore(Opts.GetCachePath("auditcursor.json"))
+	if err != nil {
+		m.Logger().Fatal(err)
+	}
+	m.cursorStore = cursorStore
+
+	if m.cursorStore != nil {
+		if exists, err := m.cursorStore.Read(m.Context(), &m.cursor); err != nil {
+			m.Logger().Warnf("unable to restore audit log cursor, starting from audit history duration: %v", err)
+			m.cursor = auditCursor{}
+		} else if exists && m.cursor.Timestamp != nil {
+			m.Logger().Infof("restored audit log cursor %v", m.cursor.Timestamp.UTC().String())
+		}
+	}
+
+	m.prometheus.auditEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azure_devops_audit_events",
+			Help: "Azure DevOps audit log events",
+		},
+		[]string{
+			"category",
+			"area",
+			"actionID",
+		},
+	)
+	m.Collector.RegisterMetricList("auditEvents", m.prometheus.auditEvents, false)
+
+	m.prometheus.auditAlert = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "azure_devops_audit_alert",
+			Help: "Azure DevOps audit log events of sensitive actions",
+		},
+		[]string{
+			"alert",
+			"actionID",
+			"actor",
+			"projectID",
+		},
+	)
+	m.Collector.RegisterMetricList("auditAlert", m.prometheus.auditAlert, false)
+
+	m.prometheus.auditCursor = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_audit_cursor",
+			Help: "Azure DevOps audit log cursor (timestamp of latest processed event)",
+		},
+		[]string{},
+	)
+	m.Collector.RegisterMetricList("auditCursor", m.prometheus.auditCursor, true)
+}
+
+func (m *MetricsCollectorAudit) Reset() {}
+
+func (m *MetricsCollectorAudit) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	endTime := time.Now()
+	startTime := endTime.Add(-Opts.Audit.HistoryDuration)
+	if m.cursor.Timestamp != nil {
+		startTime = *m.cursor.Timestamp
+	}
+
+	list, err := AzureDevopsClient.ListAuditLog(startTime, endTime)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	auditEventsMetric := m.Collector.GetMetricList("auditEvents")
+	auditAlertMetric := m.Collector.GetMetricList("auditAlert")
+	auditCursorMetric := m.Collector.GetMetricList("auditCursor")
+
+	events, cursor := m.cursor.advance(list.List)
+	for _, event := range events {
+		auditEventsMetric.Add(prometheus.Labels{
+			"category": event.Category,
+			"area":     event.Area,
+			"actionID": event.ActionId,
+		}, 1)
+
+		for _, alert := range m.alerts {
+			if alert.Pattern.MatchString(event.ActionId) {
+				auditAlertMetric.Add(prometheus.Labels{
+					"alert":     alert.Name,
+					"actionID":  event.ActionId,
+					"actor":     event.Actor(),
+					"projectID": event.ProjectId,
+				}, 1)
+			}
+		}
+	}
+
+	if cursor.Timestamp != nil {
+		auditCursorMetric.AddTime(prometheus.Labels{}, *cursor.Timestamp)
+	}
+
+	m.cursor = cursor
+	if m.cursorStore != nil {
+		if err := m.cursorStore.Write(ctx, m.cursor); err != nil {
+			logger.With(zap.String("cache", m.cursorStore.raw)).Errorf("unable to persist audit log cursor: %v", err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+func TestAuditCursorAdvance(t *testing.T) {
+	t1 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	event := func(id string, timestamp time.Time) devopsClient.AuditLogEntry {
+		return devopsClient.AuditLogEntry{Id: id, Timestamp: timestamp}
+	}
+
+	tests := []struct {
+		name           string
+		cursor         auditCursor
+		events         []devopsClient.AuditLogEntry
+		expectedEvents []string
+		expectedCursor auditCursor
+	}{
+		{
+			name:           "no cursor, no events",
+			cursor:         auditCursor{},
+			events:         nil,
+			expectedEvents: nil,
+			expectedCursor: auditCursor{EventIds: []string{}},
+		},
+		{
+			name:           "no cursor",
+			cursor:         auditCursor{},
+			events:         []devopsClient.AuditLogEntry{event("a", t2), event("b", t1), event("c", t2)},
+			expectedEvents: []string{"a", "b", "c"},
+			expectedCursor: auditCursor{Timestamp: &t2, EventIds: []string{"a", "c"}},
+		},
+		{
+			name:           "skip events before cursor",
+			cursor:         auditCursor{Timestamp: &t2, EventIds: []string{"a"}},
+			events:         []devopsClient.AuditLogEntry{event("b", t1), event("c", t3)},
+			expectedEvents: []string{"c"},
+			expectedCursor: auditCursor{Timestamp: &t3, EventIds: []string{"c"}},
+		},
+		{
+			name:           "skip processed events at cursor timestamp",
+			cursor:         auditCursor{Timestamp: &t2, EventIds: []string{"a"}},
+			events:         []devopsClient.AuditLogEntry{event("a", t2), event("c", t2)},
+			expectedEvents: []string{"c"},
+			expectedCursor: auditCursor{Timestamp: &t2, EventIds: []string{"a", "c"}},
+		},
+		{
+			name:           "no new events",
+			cursor:         auditCursor{Timestamp: &t2, EventIds: []string{"a"}},
+			events:         []devopsClient.AuditLogEntry{event("a", t2)},
+			expectedEvents: nil,
+			expectedCursor: auditCursor{Timestamp: &t2, EventIds: []string{"a"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events, cursor := test.cursor.advance(test.events)
+
+			var eventIds []string
+			for _, event := range events {
+				eventIds = append(eventIds, event.Id)
+			}
+			if !reflect.DeepEqual(eventIds, test.expectedEvents) {
+				t.Errorf("expected events %v, got %v", test.expectedEvents, eventIds)
+			}
+
+			if !reflect.DeepEqual(cursor, test.expectedCursor) {
+				t.Errorf("expected cursor %v, got %v", test.expectedCursor, cursor)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -28,14 +29,50 @@ type MetricsCollectorBuild struct {
 		buildTask  *prometheus.GaugeVec
 		buildTag   *prometheus.GaugeVec
 
+		buildArtifact            *prometheus.GaugeVec
+		buildArtifacts           *prometheus.GaugeVec
+		buildDefinitionArtifacts *prometheus.GaugeVec
+		buildRetention           *prometheus.GaugeVec
+
+		buildIssue *prometheus.GaugeVec
+
+		buildRetries *prometheus.GaugeVec
+		buildFlaky   *prometheus.GaugeVec
+
 		buildTimeProject *prometheus.SummaryVec
 		jobTimeProject   *prometheus.SummaryVec
 	}
+
+	issueLogPatterns []*regexp.Regexp
+
+	// artifacts of finished builds don't change, keep them across scrapes
+	buildArtifactCache map[string]devopsClient.BuildArtifactList
+}
+
+type buildRetryKey struct {
+	BuildDefinitionId int64
+	Type              string
+	Name              string
+}
+
+type buildFlakyStats struct {
+	Builds              int64
+	Retried             int64
+	SucceededAfterRetry int64
+}
+
+type buildIssueKey struct {
+	BuildDefinitionId int64
+	TaskName          string
+	Type              string
+	Signature         string
 }
 
 func (m *MetricsCollectorBuild) Setup(collector *collector.Collector) {
 	m.Processor.Setup(collector)
 
+	m.buildArtifactCache = map[string]devopsClient.BuildArtifactList{}
+
 	m.prometheus.build = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_build_info",
@@ -186,6 +223,112 @@ func (m *MetricsCollectorBuild) Setup(collector *collector.Collector) {
 		},
 	)
 	m.Collector.RegisterMetricList("buildDefinition", m.prometheus.buildDefinition, true)
+
+	m.prometheus.buildRetries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_build_retries",
+			Help: "Azure DevOps build retries (attempts after the first one) per build definition, stage, job and task within the build history duration",
+		},
+		[]string{
+			"projectID",
+			"buildDefinitionID",
+			"type",
+			"name",
+		},
+	)
+	m.Collector.RegisterMetricList("buildRetries", m.prometheus.buildRetries, true)
+
+	m.prometheus.buildFlaky = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_build_flaky",
+			Help: "Azure DevOps build flakiness per build definition (builds, retried builds, builds succeeded after retry and ratio) within the build history duration",
+		},
+		[]string{
+			"projectID",
+			"buildDefinitionID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("buildFlaky", m.prometheus.buildFlaky, true)
+
+	if Opts.AzureDevops.FetchBuildIssues {
+		m.prometheus.buildIssue = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_build_issue",
+				Help: "Azure DevOps build issues (errors, warnings and matching log lines) of failed tasks per normalized message signature",
+			},
+			[]string{
+				"projectID",
+				"buildDefinitionID",
+				"taskName",
+				"type",
+				"signature",
+			},
+		)
+		m.Collector.RegisterMetricList("buildIssue", m.prometheus.buildIssue, true)
+
+		for _, pattern := range Opts.AzureDevops.BuildIssueLogPattern {
+			m.issueLogPatterns = append(m.issueLogPatterns, regexp.MustCompile(pattern))
+		}
+	}
+
+	if Opts.AzureDevops.FetchBuildArtifacts {
+		m.prometheus.buildArtifact = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_build_artifact",
+				Help: "Azure DevOps build artifact size (bytes)",
+			},
+			[]string{
+				"projectID",
+				"buildID",
+				"buildDefinitionID",
+				"artifactName",
+				"artifactType",
+			},
+		)
+		m.Collector.RegisterMetricList("buildArtifact", m.prometheus.buildArtifact, true)
+
+		m.prometheus.buildArtifacts = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_build_artifacts",
+				Help: "Azure DevOps build artifact count and size (bytes)",
+			},
+			[]string{
+				"projectID",
+				"buildID",
+				"buildDefinitionID",
+				"type",
+			},
+		)
+		m.Collector.RegisterMetricList("buildArtifacts", m.prometheus.buildArtifacts, true)
+
+		m.prometheus.buildDefinitionArtifacts = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_build_definition_artifacts",
+				Help: "Azure DevOps build artifact count and size (bytes) per build definition (of fetched builds)",
+			},
+			[]string{
+				"projectID",
+				"buildDefinitionID",
+				"type",
+			},
+		)
+		m.Collector.RegisterMetricList("buildDefinitionArtifacts", m.prometheus.buildDefinitionArtifacts, true)
+
+		m.prometheus.buildRetention = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_build_retention",
+				Help: "Azure DevOps build retention (retention lease count, retained forever, retained by release)",
+			},
+			[]string{
+				"projectID",
+				"buildID",
+				"buildDefinitionID",
+				"type",
+			},
+		)
+		m.Collector.RegisterMetricList("buildRetention", m.prometheus.buildRetention, true)
+	}
 }
 
 func (m *MetricsCollectorBuild) Reset() {}
@@ -194,14 +337,26 @@ func (m *MetricsCollectorBuild) Collect(callback chan<- func()) {
 	ctx := m.Context()
 	logger := m.Logger()
 
+	cacheKeys := map[string]bool{}
+
 	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
 		projectLogger := logger.With(zap.String("project", project.Name))
 		m.collectDefinition(ctx, projectLogger, callback, project)
 		m.collectBuilds(ctx, projectLogger, callback, project)
-		m.collectBuildsTimeline(ctx, projectLogger, callback, project)
+		buildList := m.collectBuildsTimeline(ctx, projectLogger, callback, project)
 		if nil != Opts.AzureDevops.TagsSchema {
 			m.collectBuildsTags(ctx, projectLogger, callback, project)
 		}
+		if Opts.AzureDevops.FetchBuildArtifacts {
+			m.collectBuildsArtifacts(ctx, projectLogger, callback, project, buildList, cacheKeys)
+		}
+	}
+
+	// remove artifacts of builds which are out of the build history
+	for key := range m.buildArtifactCache {
+		if !cacheKeys[key] {
+			delete(m.buildArtifactCache, key)
+		}
 	}
 }
 
@@ -302,7 +457,8 @@ func (m *MetricsCollectorBuild) collectBuilds(ctx context.Context, logger *zap.S
 	}
 }
 
-func (m *MetricsCollectorBuild) collectBuildsTimeline(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+// collectBuildsTimeline exports the timeline of builds, returns the fetched build list for further processing
+func (m *MetricsCollectorBuild) collectBuildsTimeline(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) (list devopsClient.BuildList) {
 	minTime := time.Now().Add(-Opts.Limit.BuildHistoryDuration)
 
 	statusFilter := "completed"
@@ -322,7 +478,12 @@ func (m *MetricsCollectorBuild) collectBuildsTimeline(ctx context.Context, logge
 	buildJobMetric := m.Collector.GetMetricList("buildJob")
 	buildTaskMetric := m.Collector.GetMetricList("buildTask")
 
+	issueCount := map[buildIssueKey]float64{}
+	retryCount := map[buildRetryKey]int64{}
+	flakyStats := map[int64]*buildFlakyStats{}
+
 	for _, build := range list.List {
+		buildRetried := false
 
 		timelineRecordList, _ := AzureDevopsClient.ListBuildTimeline(project.Id, int64ToString(build.Id))
 		for _, timelineRecord := range timelineRecordList.List {
@@ -331,6 +492,24 @@ func (m *MetricsCollectorBuild) collectBuildsTimeline(ctx context.Context, logge
 				continue
 			}
 
+			if retries := timelineRecord.Retries(); retries > 0 {
+				retryType := strings.ToLower(timelineRecord.RecordType)
+				retryName := timelineRecord.Name
+				if retryType == "task" {
+					retryName = timelineRecord.TaskName()
+				}
+
+				retryCount[buildRetryKey{
+					BuildDefinitionId: build.Definition.Id,
+					Type:              retryType,
+					Name:              retryName,
+				}] += retries
+
+				if retryType == "stage" || retryType == "phase" || retryType == "job" {
+					buildRetried = true
+				}
+			}
+
 			if timelineRecord.Result == "" {
 				timelineRecord.Result = "unknown"
 			}
@@ -646,6 +825,107 @@ func (m *MetricsCollectorBuild) collectBuildsTimeline(ctx context.Context, logge
 					"result":            timelineRecord.Result,
 					"type":              "duration",
 				}, timelineRecord.FinishTime.Sub(timelineRecord.StartTime))
+
+				if Opts.AzureDevops.FetchBuildIssues && timelineRecord.Result == "failed" {
+					m.collectTimelineRecordIssues(logger, project, build, timelineRecord, issueCount)
+				}
+			}
+		}
+
+		if build.Status == "completed" {
+			stats, exists := flakyStats[build.Definition.Id]
+			if !exists {
+				stats = &buildFlakyStats{}
+				flakyStats[build.Definition.Id] = stats
+			}
+
+			stats.Builds++
+			if buildRetried {
+				stats.Retried++
+				if build.Result == "succeeded" {
+					stats.SucceededAfterRetry++
+				}
+			}
+		}
+	}
+
+	buildRetriesMetric := m.Collector.GetMetricList("buildRetries")
+	for retry, count := range retryCount {
+		buildRetriesMetric.Add(prometheus.Labels{
+			"projectID":         project.Id,
+			"buildDefinitionID": int64ToString(retry.BuildDefinitionId),
+			"type":              retry.Type,
+			"name":              retry.Name,
+		}, float64(count))
+	}
+
+	buildFlakyMetric := m.Collector.GetMetricList("buildFlaky")
+	for buildDefinitionId, stats := range flakyStats {
+		flakyLabels := func(valueType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":         project.Id,
+				"buildDefinitionID": int64ToString(buildDefinitionId),
+				"type":              valueType,
+			}
+		}
+
+		ratio := float64(0)
+		if stats.Builds > 0 {
+			ratio = float64(stats.SucceededAfterRetry) / float64(stats.Builds)
+		}
+
+		buildFlakyMetric.Add(flakyLabels("builds"), float64(stats.Builds))
+		buildFlakyMetric.Add(flakyLabels("retried"), float64(stats.Retried))
+		buildFlakyMetric.Add(flakyLabels("succeededAfterRetry"), float64(stats.SucceededAfterRetry))
+		buildFlakyMetric.Add(flakyLabels("ratio"), ratio)
+	}
+
+	if Opts.AzureDevops.FetchBuildIssues {
+		buildIssueMetric := m.Collector.GetMetricList("buildIssue")
+		for issue, count := range issueCount {
+			buildIssueMetric.Add(prometheus.Labels{
+				"projectID":         project.Id,
+				"buildDefinitionID": int64ToString(issue.BuildDefinitionId),
+				"taskName":          issue.TaskName,
+				"type":              issue.Type,
+				"signature":         issue.Signature,
+			}, count)
+		}
+	}
+
+	return
+}
+
+func (m *MetricsCollectorBuild) collectTimelineRecordIssues(logger *zap.SugaredLogger, project devopsClient.Project, build devopsClient.Build, timelineRecord devopsClient.TimelineRecord, issueCount map[buildIssueKey]float64) {
+	for _, issue := range timelineRecord.Issues {
+		issueCount[buildIssueKey{
+			BuildDefinitionId: build.Definition.Id,
+			TaskName:          timelineRecord.TaskName(),
+			Type:              issue.Type,
+			Signature:         normalizeIssueMessage(issue.Message),
+		}]++
+	}
+
+	if len(m.issueLogPatterns) == 0 || timelineRecord.Log == nil {
+		return
+	}
+
+	lines, err := AzureDevopsClient.GetBuildLogLines(project.Id, int64ToString(build.Id), timelineRecord.Log.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, line := range lines {
+		for _, pattern := range m.issueLogPatterns {
+			if pattern.MatchString(line) {
+				issueCount[buildIssueKey{
+					BuildDefinitionId: build.Definition.Id,
+					TaskName:          timelineRecord.TaskName(),
+					Type:              "log",
+					Signature:         normalizeIssueMessage(line),
+				}]++
+				break
 			}
 		}
 	}
@@ -717,3 +997,90 @@ func (m *MetricsCollectorBuild) collectBuildsTags(ctx context.Context, logger *z
 		}
 	}
 }
+
+func (m *MetricsCollectorBuild) collectBuildsArtifacts(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, list devopsClient.BuildList, cacheKeys map[string]bool) {
+	buildArtifactMetric := m.Collector.GetMetricList("buildArtifact")
+	buildArtifactsMetric := m.Collector.GetMetricList("buildArtifacts")
+	buildDefinitionArtifactsMetric := m.Collector.GetMetricList("buildDefinitionArtifacts")
+	buildRetentionMetric := m.Collector.GetMetricList("buildRetention")
+
+	definitionArtifactCount := map[int64]float64{}
+	definitionArtifactSize := map[int64]float64{}
+
+	for _, build := range list.List {
+		if build.Status != "completed" {
+			continue
+		}
+
+		buildLabels := func(valueType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":         project.Id,
+				"buildID":           int64ToString(build.Id),
+				"buildDefinitionID": int64ToString(build.Definition.Id),
+				"type":              valueType,
+			}
+		}
+
+		cacheKey := project.Id + "/" + int64ToString(build.Id)
+		cacheKeys[cacheKey] = true
+
+		var err error
+		artifactList, exists := m.buildArtifactCache[cacheKey]
+		if !exists {
+			artifactList, err = AzureDevopsClient.ListBuildArtifacts(project.Id, int64ToString(build.Id))
+			if err == nil {
+				m.buildArtifactCache[cacheKey] = artifactList
+			}
+		}
+
+		if err == nil {
+			artifactSize := float64(0)
+			for _, artifact := range artifactList.List {
+				size := artifact.Size()
+				if size != nil {
+					artifactSize += *size
+				}
+
+				buildArtifactMetric.AddIfNotNil(prometheus.Labels{
+					"projectID":         project.Id,
+					"buildID":           int64ToString(build.Id),
+					"buildDefinitionID": int64ToString(build.Definition.Id),
+					"artifactName":      artifact.Name,
+					"artifactType":      artifact.Resource.Type,
+				}, size)
+			}
+
+			buildArtifactsMetric.Add(buildLabels("count"), float64(len(artifactList.List)))
+			buildArtifactsMetric.Add(buildLabels("size"), artifactSize)
+
+			definitionArtifactCount[build.Definition.Id] += float64(len(artifactList.List))
+			definitionArtifactSize[build.Definition.Id] += artifactSize
+		} else {
+			logger.Error(err)
+		}
+
+		leaseList, err := AzureDevopsClient.ListBuildRetentionLeases(project.Id, int64ToString(build.Id))
+		if err == nil {
+			buildRetentionMetric.Add(buildLabels("leases"), float64(len(leaseList.List)))
+		} else {
+			logger.Error(err)
+		}
+
+		buildRetentionMetric.AddBool(buildLabels("retainedForever"), build.KeepForever)
+		buildRetentionMetric.AddBool(buildLabels("retainedByRelease"), build.RetainedByRelease)
+	}
+
+	for buildDefinitionId, count := range definitionArtifactCount {
+		buildDefinitionArtifactsMetric.Add(prometheus.Labels{
+			"projectID":         project.Id,
+			"buildDefinitionID": int64ToString(buildDefinitionId),
+			"type":              "count",
+		}, count)
+
+		buildDefinitionArtifactsMetric.Add(prometheus.Labels{
+			"projectID":         project.Id,
+			"buildDefinitionID": int64ToString(buildDefinitionId),
+			"type":              "size",
+		}, definitionArtifactSize[buildDefinitionId])
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorDeploymentGroup struct {
+	collector.Processor
+
+	prometheus struct {
+		deploymentGroup                 *prometheus.GaugeVec
+		deploymentGroupSize             *prometheus.GaugeVec
+		deploymentGroupTarget           *prometheus.GaugeVec
+		deploymentGroupTargetStatus     *prometheus.GaugeVec
+		deploymentGroupTargetTag        *prometheus.GaugeVec
+		deploymentGroupTargetDeployment *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorDeploymentGroup) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.deploymentGroup = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_info",
+			Help: "Azure DevOps deploymentgroup",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+			"deploymentGroupName",
+			"agentPoolID",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroup", m.prometheus.deploymentGroup, true)
+
+	m.prometheus.deploymentGroupSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_size",
+			Help: "Azure DevOps deploymentgroup number of targets",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroupSize", m.prometheus.deploymentGroupSize, true)
+
+	m.prometheus.deploymentGroupTarget = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_target_info",
+			Help: "Azure DevOps deploymentgroup target",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+			"deploymentTargetID",
+			"deploymentTargetName",
+			"agentID",
+			"agentVersion",
+			"agentOs",
+			"enabled",
+			"status",
+			"tags",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroupTarget", m.prometheus.deploymentGroupTarget, true)
+
+	m.prometheus.deploymentGroupTargetStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_target_status",
+			Help: "Azure DevOps deploymentgroup target status",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+			"deploymentTargetID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroupTargetStatus", m.prometheus.deploymentGroupTargetStatus, true)
+
+	m.prometheus.deploymentGroupTargetTag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_target_tag",
+			Help: "Azure DevOps deploymentgroup target tags",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+			"deploymentTargetID",
+			"tag",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroupTargetTag", m.prometheus.deploymentGroupTargetTag, true)
+
+	m.prometheus.deploymentGroupTargetDeployment = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_deploymentgroup_target_deployment",
+			Help: "Azure DevOps deploymentgroup target last deployment (finish time)",
+		},
+		[]string{
+			"projectID",
+			"deploymentGroupID",
+			"deploymentTargetID",
+			"jobRequestId",
+			"definitionID",
+			"definitionName",
+			"ownerID",
+			"ownerName",
+			"planType",
+			"result",
+		},
+	)
+	m.Collector.RegisterMetricList("deploymentGroupTargetDeployment", m.prometheus.deploymentGroupTargetDeployment, true)
+}
+
+func (m *MetricsCollectorDeploymentGroup) Reset() {}
+
+func (m *MetricsCollectorDeploymentGroup) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectDeploymentGroups(ctx, projectLogger, callback, project)
+	}
+}
+
+func (m *MetricsCollectorDeploymentGroup) collectDeploymentGroups(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListDeploymentGroups(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	deploymentGroupMetric := m.Collector.GetMetricList("deploymentGroup")
+	deploymentGroupSizeMetric := m.Collector.GetMetricList("deploymentGroupSize")
+
+	for _, deploymentGroup := range list.List {
+		deploymentGroupMetric.AddInfo(prometheus.Labels{
+			"projectID":           project.Id,
+			"deploymentGroupID":   int64ToString(deploymentGroup.Id),
+			"deploymentGroupName": deploymentGroup.Name,
+			"agentPoolID":         int64ToString(deploymentGroup.Pool.Id),
+		})
+
+		deploymentGroupSizeMetric.Add(prometheus.Labels{
+			"projectID":         project.Id,
+			"deploymentGroupID": int64ToString(deploymentGroup.Id),
+		}, float64(deploymentGroup.MachineCount))
+
+		deploymentGroupLogger := logger.With(zap.Int64("deploymentGroupId", deploymentGroup.Id))
+		m.collectDeploymentTargets(ctx, deploymentGroupLogger, callback, project, deploymentGroup)
+	}
+}
+
+func (m *MetricsCollectorDeploymentGroup) collectDeploymentTargets(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, deploymentGroup devopsClient.DeploymentGroup) {
+	list, err := AzureDevopsClient.ListDeploymentTargets(project.Id, deploymentGroup.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	deploymentGroupTargetMetric := m.Collector.GetMetricList("deploymentGroupTarget")
+	deploymentGroupTargetStatusMetric := m.Collector.GetMetricList("deploymentGroupTargetStatus")
+	deploymentGroupTargetTagMetric := m.Collector.GetMetricList("deploymentGroupTargetTag")
+	deploymentGroupTargetDeploymentMetric := m.Collector.GetMetricList("deploymentGroupTargetDeployment")
+
+	for _, deploymentTarget := range list.List {
+		deploymentTargetId := int64ToString(deploymentTarget.Id)
+
+		deploymentGroupTargetMetric.AddInfo(prometheus.Labels{
+			"projectID":            project.Id,
+			"deploymentGroupID":    int64ToString(deploymentGroup.Id),
+			"deploymentTargetID":   deploymentTargetId,
+			"deploymentTargetName": deploymentTarget.Agent.Name,
+			"agentID":              int64ToString(deploymentTarget.Agent.Id),
+			"agentVersion":         deploymentTarget.Agent.Version,
+			"agentOs":              deploymentTarget.Agent.OsDescription,
+			"enabled":              to.BoolString(deploymentTarget.Agent.Enabled),
+			"status":               deploymentTarget.Agent.Status,
+			"tags":                 strings.Join(deploymentTarget.Tags, ","),
+		})
+
+		deploymentGroupTargetStatusMetric.AddBool(prometheus.Labels{
+			"projectID":          project.Id,
+			"deploymentGroupID":  int64ToString(deploymentGroup.Id),
+			"deploymentTargetID": deploymentTargetId,
+			"type":               "online",
+		}, strings.EqualFold(deploymentTarget.Agent.Status, "online"))
+
+		deploymentGroupTargetStatusMetric.AddTime(prometheus.Labels{
+			"projectID":          project.Id,
+			"deploymentGroupID":  int64ToString(deploymentGroup.Id),
+			"deploymentTargetID": deploymentTargetId,
+			"type":               "created",
+		}, deploymentTarget.Agent.CreatedOn)
+
+		for _, tag := range deploymentTarget.Tags {
+			deploymentGroupTargetTagMetric.AddInfo(prometheus.Labels{
+				"projectID":          project.Id,
+				"deploymentGroupID":  int64ToString(deploymentGroup.Id),
+				"deploymentTargetID": deploymentTargetId,
+				"tag":                tag,
+			})
+		}
+
+		if lastRequest := deploymentTarget.Agent.LastCompletedRequest; lastRequest != nil && lastRequest.FinishTime != nil {
+			deploymentGroupTargetDeploymentMetric.AddTime(prometheus.Labels{
+				"projectID":          project.Id,
+				"deploymentGroupID":  int64ToString(deploymentGroup.Id),
+				"deploymentTargetID": deploymentTargetId,
+				"jobRequestId":       int64ToString(lastRequest.RequestId),
+				"definitionID":       int64ToString(lastRequest.Definition.Id),
+				"definitionName":     lastRequest.Definition.Name,
+				"ownerID":            int64ToString(lastRequest.Owner.Id),
+				"ownerName":          lastRequest.Owner.Name,
+				"planType":           lastRequest.PlanType,
+				"result":             lastRequest.Result,
+			}, *lastRequest.FinishTime)
+
+			deploymentGroupTargetStatusMetric.AddTime(prometheus.Labels{
+				"projectID":          project.Id,
+				"deploymentGroupID":  int64ToString(deploymentGroup.Id),
+				"deploymentTargetID": deploymentTargetId,
+				"type":               "lastDeployment",
+			}, *lastRequest.FinishTime)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorDora struct {
+	collector.Processor
+
+	environmentPatterns []*regexp.Regexp
+	pipelinePatterns    []*regexp.Regexp
+
+	// oldest change (commit) of deployed builds, builds don't change so they are only fetched once
+	buildChangeCache map[string]*time.Time
+
+	prometheus struct {
+		doraDeployments   *prometheus.GaugeVec
+		doraStats         *prometheus.GaugeVec
+		doraLeadTime      *prometheus.HistogramVec
+		doraTimeToRestore *prometheus.HistogramVec
+	}
+}
+
+// doraDeployment is a finished production deployment of a service (release definition or pipeline)
+type doraDeployment struct {
+	Service     string
+	Source      string
+	Environment string
+	Result      string
+	FinishTime  time.Time
+
+	// deployed build (for lead time of changes)
+	BuildProject string
+	BuildId      int64
+}
+
+func (m *MetricsCollectorDora) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.buildChangeCache = map[string]*time.Time{}
+
+	for _, pattern := range Opts.Dora.Environment {
+		m.environmentPatterns = append(m.environmentPatterns, regexp.MustCompile(pattern))
+	}
+
+	for _, pattern := range Opts.Dora.Pipeline {
+		m.pipelinePatterns = append(m.pipelinePatterns, regexp.MustCompile(pattern))
+	}
+
+	m.prometheus.doraDeployments = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_dora_deployments",
+			Help: "Azure DevOps DORA number of production deployments within the dora history duration",
+		},
+		[]string{
+			"projectID",
+			"service",
+			"source",
+			"environment",
+			"result",
+		},
+	)
+	m.Collector.RegisterMetricList("doraDeployments", m.prometheus.doraDeployments, true)
+
+	m.prometheus.doraStats = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_dora_stats",
+			Help: "Azure DevOps DORA metrics (deployment frequency per day, change failure rate, average lead time and time to restore) within the dora history duration",
+		},
+		[]string{
+			"projectID",
+			"service",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("doraStats", m.prometheus.doraStats, true)
+
+	m.prometheus.doraLeadTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_dora_lead_time",
+			Help: "Azure DevOps DORA lead time for changes (seconds from oldest commit to successful production deployment)",
+			Buckets: []float64{
+				1 * 60 * 60,       // 1h
+				4 * 60 * 60,       // 4h
+				12 * 60 * 60,      // 12h
+				1 * 24 * 60 * 60,  // 1d
+				2 * 24 * 60 * 60,  // 2d
+				4 * 24 * 60 * 60,  // 4d
+				7 * 24 * 60 * 60,  // 1w
+				14 * 24 * 60 * 60, // 2w
+				28 * 24 * 60 * 60, // 4w
+			},
+		},
+		[]string{
+			"projectID",
+			"service",
+		},
+	)
+	m.Collector.RegisterMetricList("doraLeadTime", m.prometheus.doraLeadTime, false)
+
+	m.prometheus.doraTimeToRestore = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_dora_time_to_restore",
+			Help: "Azure DevOps DORA time to restore (seconds from failed to next successful production deployment)",
+			Buckets: []float64{
+				15 * 60,          // 15m
+				1 * 60 * 60,      // 1h
+				4 * 60 * 60,      // 4h
+				12 * 60 * 60,     // 12h
+				1 * 24 * 60 * 60, // 1d
+				2 * 24 * 60 * 60, // 2d
+				4 * 24 * 60 * 60, // 4d
+				7 * 24 * 60 * 60, // 1w
+			},
+		},
+		[]string{
+			"projectID",
+			"service",
+		},
+	)
+	m.Collector.RegisterMetricList("doraTimeToRestore", m.prometheus.doraTimeToRestore, false)
+}
+
+func (m *MetricsCollectorDora) Reset() {}
+
+func (m *MetricsCollectorDora) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	deployedBuilds := map[string]bool{}
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectDora(ctx, projectLogger, callback, project, deployedBuilds)
+	}
+
+	// cleanup builds which are no longer deployed within the dora history duration
+	for key := range m.buildChangeCache {
+		if !deployedBuilds[key] {
+			delete(m.buildChangeCache, key)
+		}
+	}
+}
+
+func (m *MetricsCollectorDora) collectDora(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, deployedBuilds map[string]bool) {
+	minTime := time.Now().Add(-Opts.Dora.HistoryDuration)
+
+	// durations are only observed once (for deployments finished since last run)
+	durationMinTime := time.Now().Add(-*m.Collector.GetScapeTime())
+	if val := m.Collector.GetLastScapeTime(); val != nil {
+		durationMinTime = *val
+	}
+
+	deploymentList := m.fetchReleaseDeployments(logger, project, minTime)
+	deploymentList = append(deploymentList, m.fetchEnvironmentDeployments(logger, project, minTime)...)
+
+	sort.Slice(deploymentList, func(i, j int) bool {
+		return deploymentList[i].FinishTime.Before(deploymentList[j].FinishTime)
+	})
+
+	doraDeploymentsMetric := m.Collector.GetMetricList("doraDeployments")
+	doraStatsMetric := m.Collector.GetMetricList("doraStats")
+	doraLeadTimeMetric := m.Collector.GetMetricList("doraLeadTime")
+	doraTimeToRestoreMetric := m.Collector.GetMetricList("doraTimeToRestore")
+
+	type deploymentKey struct {
+		Service     string
+		Source      string
+		Environment string
+		Result      string
+	}
+
+	type restoreKey struct {
+		Service     string
+		Environment string
+	}
+
+	type serviceStats struct {
+		Deployments   int64
+		Failed        int64
+		LeadTime      []time.Duration
+		TimeToRestore []time.Duration
+	}
+
+	deploymentCount := map[deploymentKey]int64{}
+	failedSince := map[restoreKey]time.Time{}
+	stats := map[string]*serviceStats{}
+
+	for _, deployment := range deploymentList {
+		deploymentCount[deploymentKey{
+			Service:     deployment.Service,
+			Source:      deployment.Source,
+			Environment: deployment.Environment,
+			Result:      deployment.Result,
+		}]++
+
+		service, exists := stats[deployment.Service]
+		if !exists {
+			service = &serviceStats{}
+			stats[deployment.Service] = service
+		}
+		service.Deployments++
+
+		serviceLabels := prometheus.Labels{
+			"projectID": project.Id,
+			"service":   deployment.Service,
+		}
+
+		// time to restore is tracked per environment as services might be deployed into multiple production environments
+		restore := restoreKey{Service: deployment.Service, Environment: deployment.Environment}
+		if deployment.Result == "failed" {
+			service.Failed++
+			if _, exists := failedSince[restore]; !exists {
+				failedSince[restore] = deployment.FinishTime
+			}
+			continue
+		}
+
+		if since, exists := failedSince[restore]; exists {
+			timeToRestore := deployment.FinishTime.Sub(since)
+			service.TimeToRestore = append(service.TimeToRestore, timeToRestore)
+			if deployment.FinishTime.After(durationMinTime) {
+				doraTimeToRestoreMetric.AddDuration(serviceLabels, timeToRestore)
+			}
+			delete(failedSince, restore)
+		}
+
+		// lead time for changes (oldest commit of the deployed build)
+		if deployment.BuildId > 0 {
+			cacheKey := deployment.BuildProject + "/" + int64ToString(deployment.BuildId)
+			deployedBuilds[cacheKey] = true
+
+			oldestChange, exists := m.buildChangeCache[cacheKey]
+			if !exists {
+				changeList, err := AzureDevopsClient.ListBuildChanges(deployment.BuildProject, int64ToString(deployment.BuildId))
+				if err == nil {
+					oldestChange = changeList.Oldest()
+					m.buildChangeCache[cacheKey] = oldestChange
+				} else {
+					logger.With(zap.Int64("buildId", deployment.BuildId)).Error(err)
+				}
+			}
+
+			if oldestChange != nil && deployment.FinishTime.After(*oldestChange) {
+				leadTime := deployment.FinishTime.Sub(*oldestChange)
+				service.LeadTime = append(service.LeadTime, leadTime)
+				if deployment.FinishTime.After(durationMinTime) {
+					doraLeadTimeMetric.AddDuration(serviceLabels, leadTime)
+				}
+			}
+		}
+	}
+
+	for deployment, count := range deploymentCount {
+		doraDeploymentsMetric.Add(prometheus.Labels{
+			"projectID":   project.Id,
+			"service":     deployment.Service,
+			"source":      deployment.Source,
+			"environment": deployment.Environment,
+			"result":      deployment.Result,
+		}, float64(count))
+	}
+
+	historyDays := Opts.Dora.HistoryDuration.Hours() / 24
+	for serviceName, service := range stats {
+		statsLabels := func(statsType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID": project.Id,
+				"service":   serviceName,
+				"type":      statsType,
+			}
+		}
+
+		if historyDays > 0 {
+			doraStatsMetric.Add(statsLabels("deploymentFrequency"), float64(service.Deployments)/historyDays)
+		}
+		doraStatsMetric.Add(statsLabels("changeFailureRate"), float64(service.Failed)/float64(service.Deployments))
+
+		if len(service.LeadTime) > 0 {
+			doraStatsMetric.AddDuration(statsLabels("leadTime"), averageDuration(service.LeadTime))
+		}
+
+		if len(service.TimeToRestore) > 0 {
+			doraStatsMetric.AddDuration(statsLabels("timeToRestore"), averageDuration(service.TimeToRestore))
+		}
+	}
+}
+
+// fetchReleaseDeployments returns finished deployments of (classic) releases into production stages
+func (m *MetricsCollectorDora) fetchReleaseDeployments(logger *zap.SugaredLogger, project devopsClient.Project, minTime time.Time) (list []doraDeployment) {
+	releaseDefinitionList, err := AzureDevopsClient.ListReleaseDefinitions(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, releaseDefinition := range releaseDefinitionList.List {
+		if !m.isProductionPipeline(releaseDefinition.Name) {
+			continue
+		}
+
+		deploymentList, err := AzureDevopsClient.ListReleaseDeployments(project.Id, releaseDefinition.Id)
+		if err != nil {
+			logger.With(zap.String("releaseDefinition", releaseDefinition.Name)).Error(err)
+			continue
+		}
+
+		for _, deployment := range deploymentList.List {
+			if !m.isProductionEnvironment(deployment.ReleaseEnvironment.Name) {
+				continue
+			}
+
+			completedOn := deployment.CompletedOnTime()
+			if completedOn == nil || completedOn.Before(minTime) {
+				continue
+			}
+
+			result := doraDeploymentResult(deployment.DeploymentStatus)
+			if result == "" {
+				continue
+			}
+
+			row := doraDeployment{
+				Service:     releaseDefinition.Name,
+				Source:      "release",
+				Environment: deployment.ReleaseEnvironment.Name,
+				Result:      result,
+				FinishTime:  *completedOn,
+			}
+
+			if artifact := deployment.BuildArtifact(); artifact != nil {
+				if buildId, err := strconv.ParseInt(artifact.DefinitionReference.Version.Id, 10, 64); err == nil {
+					row.BuildId = buildId
+					row.BuildProject = artifact.DefinitionReference.Project.Id
+					if row.BuildProject == "" {
+						row.BuildProject = project.Id
+					}
+				}
+			}
+
+			list = append(list, row)
+		}
+	}
+
+	return
+}
+
+// fetchEnvironmentDeployments returns finished pipeline runs deployed into production environments
+func (m *MetricsCollectorDora) fetchEnvironmentDeployments(logger *zap.SugaredLogger, project devopsClient.Project, minTime time.Time) (list []doraDeployment) {
+	environmentList, err := AzureDevopsClient.ListEnvironments(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, environment := range environmentList.List {
+		if !m.isProductionEnvironment(environment.Name) {
+			continue
+		}
+
+		recordList, err := AzureDevopsClient.ListEnvironmentDeploymentRecords(project.Id, environment.Id)
+		if err != nil {
+			logger.With(zap.String("environment", environment.Name)).Error(err)
+			continue
+		}
+
+		// a pipeline run creates one record per deployment job, merge them into one deployment per run
+		runDeployments := map[int64]*doraDeployment{}
+		for _, record := range recordList.List {
+			if record.PlanType != "Build" || !m.isProductionPipeline(record.Definition.Name) {
+				continue
+			}
+
+			if record.FinishTime == nil || record.FinishTime.Before(minTime) {
+				continue
+			}
+
+			result := doraDeploymentResult(record.Result)
+			if result == "" {
+				continue
+			}
+
+			row, exists := runDeployments[record.Owner.Id]
+			if !exists {
+				row = &doraDeployment{
+					Service:      record.Definition.Name,
+					Source:       "pipeline",
+					Environment:  environment.Name,
+					Result:       result,
+					FinishTime:   *record.FinishTime,
+					BuildProject: project.Id,
+					BuildId:      record.Owner.Id,
+				}
+				runDeployments[record.Owner.Id] = row
+			}
+
+			if result == "failed" {
+				row.Result = result
+			}
+
+			if record.FinishTime.After(row.FinishTime) {
+				row.FinishTime = *record.FinishTime
+			}
+		}
+
+		for _, row := range runDeployments {
+			list = append(list, *row)
+		}
+	}
+
+	return
+}
+
+func (m *MetricsCollectorDora) isProductionEnvironment(name string) bool {
+	for _, pattern := range m.environmentPatterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MetricsCollectorDora) isProductionPipeline(name string) bool {
+	if len(m.pipelinePatterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range m.pipelinePatterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// doraDeploymentResult normalizes release deployment and environment record results into succeeded or failed (empty if not finished or canceled)
+func doraDeploymentResult(result string) string {
+	switch strings.ToLower(result) {
+	case "succeeded":
+		return "succeeded"
+	case "failed", "partiallysucceeded":
+		return "failed"
+	}
+	return ""
+}
+
+func averageDuration(list []time.Duration) time.Duration {
+	sum := time.Duration(0)
+	for _, val := range list {
+		sum += val
+	}
+	return sum / time.Duration(len(list))
+}
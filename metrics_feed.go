@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorFeed struct {
+	collector.Processor
+
+	prometheus struct {
+		feed              *prometheus.GaugeVec
+		feedUpstream      *prometheus.GaugeVec
+		feedPackages      *prometheus.GaugeVec
+		feedPackage       *prometheus.GaugeVec
+		feedPackageStatus *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorFeed) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.feed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_feed_info",
+			Help: "Azure DevOps artifacts feed",
+		},
+		[]string{
+			"projectID",
+			"feedID",
+			"feedName",
+			"upstreamEnabled",
+		},
+	)
+	m.Collector.RegisterMetricList("feed", m.prometheus.feed, true)
+
+	m.prometheus.feedUpstream = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_feed_upstream",
+			Help: "Azure DevOps artifacts feed upstream source",
+		},
+		[]string{
+			"projectID",
+			"feedID",
+			"upstreamID",
+			"upstreamName",
+			"protocol",
+			"type",
+			"location",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("feedUpstream", m.prometheus.feedUpstream, true)
+
+	m.prometheus.feedPackages = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_feed_packages",
+			Help: "Azure DevOps artifacts feed number of packages per protocol",
+		},
+		[]string{
+			"projectID",
+			"feedID",
+			"protocol",
+		},
+	)
+	m.Collector.RegisterMetricList("feedPackages", m.prometheus.feedPackages, true)
+
+	m.prometheus.feedPackage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_feed_package_info",
+			Help: "Azure DevOps artifacts feed package",
+		},
+		[]string{
+			"projectID",
+			"feedID",
+			"packageID",
+			"packageName",
+			"protocol",
+			"latestVersion",
+		},
+	)
+	m.Collector.RegisterMetricList("feedPackage", m.prometheus.feedPackage, true)
+
+	m.prometheus.feedPackageStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_feed_package_status",
+			Help: "Azure DevOps artifacts feed package status (versions, downloads, unique users, last published and downloaded time)",
+		},
+		[]string{
+			"projectID",
+			"feedID",
+			"packageID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("feedPackageStatus", m.prometheus.feedPackageStatus, true)
+}
+
+func (m *MetricsCollectorFeed) Reset() {}
+
+func (m *MetricsCollectorFeed) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	// organization scoped feeds
+	m.collectFeeds(ctx, logger, callback, "")
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectFeeds(ctx, projectLogger, callback, project.Id)
+	}
+}
+
+// collectFeeds exports the feeds of a project (or organization scoped feeds if project is empty)
+func (m *MetricsCollectorFeed) collectFeeds(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project string) {
+	list, err := AzureDevopsClient.ListFeeds(project)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	feedMetric := m.Collector.GetMetricList("feed")
+	feedUpstreamMetric := m.Collector.GetMetricList("feedUpstream")
+
+	for _, feed := range list.List {
+		// organization scoped feed list might also contain project scoped feeds
+		if feed.ProjectId() != project {
+			continue
+		}
+
+		feedMetric.AddInfo(prometheus.Labels{
+			"projectID":       project,
+			"feedID":          feed.Id,
+			"feedName":        feed.Name,
+			"upstreamEnabled": to.BoolString(feed.UpstreamEnabled),
+		})
+
+		for _, upstream := range feed.UpstreamSources {
+			feedUpstreamMetric.AddInfo(prometheus.Labels{
+				"projectID":    project,
+				"feedID":       feed.Id,
+				"upstreamID":   upstream.Id,
+				"upstreamName": upstream.Name,
+				"protocol":     upstream.Protocol,
+				"type":         upstream.UpstreamSourceType,
+				"location":     upstream.Location,
+				"status":       upstream.Status,
+			})
+		}
+
+		m.collectFeedPackages(ctx, logger.With(zap.String("feed", feed.Name)), callback, project, feed)
+	}
+}
+
+func (m *MetricsCollectorFeed) collectFeedPackages(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project string, feed devopsClient.Feed) {
+	list, err := AzureDevopsClient.ListFeedPackages(project, feed.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	feedPackagesMetric := m.Collector.GetMetricList("feedPackages")
+	feedPackageMetric := m.Collector.GetMetricList("feedPackage")
+	feedPackageStatusMetric := m.Collector.GetMetricList("feedPackageStatus")
+
+	statusLabels := func(packageId, statusType string) prometheus.Labels {
+		return prometheus.Labels{
+			"projectID": project,
+			"feedID":    feed.Id,
+			"packageID": packageId,
+			"type":      statusType,
+		}
+	}
+
+	protocolCount := map[string]int{}
+	packageIds := []string{}
+	for _, feedPackage := range list.List {
+		protocolCount[feedPackage.ProtocolType]++
+		packageIds = append(packageIds, feedPackage.Id)
+
+		latestVersion := ""
+		if version := feedPackage.LatestVersion(); version != nil {
+			latestVersion = version.Version
+
+			if version.PublishDate != nil {
+				feedPackageStatusMetric.AddTime(statusLabels(feedPackage.Id, "lastPublished"), *version.PublishDate)
+			}
+		}
+
+		feedPackageMetric.AddInfo(prometheus.Labels{
+			"projectID":     project,
+			"feedID":        feed.Id,
+			"packageID":     feedPackage.Id,
+			"packageName":   feedPackage.Name,
+			"protocol":      feedPackage.ProtocolType,
+			"latestVersion": latestVersion,
+		})
+
+		versionCount := 0
+		for _, version := range feedPackage.Versions {
+			if !version.IsDeleted {
+				versionCount++
+			}
+		}
+		feedPackageStatusMetric.Add(statusLabels(feedPackage.Id, "versions"), float64(versionCount))
+	}
+
+	for protocol, count := range protocolCount {
+		feedPackagesMetric.Add(prometheus.Labels{
+			"projectID": project,
+			"feedID":    feed.Id,
+			"protocol":  protocol,
+		}, float64(count))
+	}
+
+	if len(packageIds) == 0 {
+		return
+	}
+
+	// download statistics
+	metricsList, err := AzureDevopsClient.ListFeedPackageMetrics(project, feed.Id, packageIds)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, packageMetrics := range metricsList.List {
+		feedPackageStatusMetric.Add(statusLabels(packageMetrics.PackageId, "downloads"), packageMetrics.DownloadCount)
+		feedPackageStatusMetric.Add(statusLabels(packageMetrics.PackageId, "downloadUniqueUsers"), packageMetrics.DownloadUniqueUsers)
+
+		if packageMetrics.LastDownloaded != nil {
+			feedPackageStatusMetric.AddTime(statusLabels(packageMetrics.PackageId, "lastDownloaded"), *packageMetrics.LastDownloaded)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorLibrary struct {
+	collector.Processor
+
+	prometheus struct {
+		variableGroup             *prometheus.GaugeVec
+		variableGroupStatus       *prometheus.GaugeVec
+		variableGroupPipeline     *prometheus.GaugeVec
+		variableGroupSecretExpiry *prometheus.GaugeVec
+
+		secureFile         *prometheus.GaugeVec
+		secureFileStatus   *prometheus.GaugeVec
+		secureFilePipeline *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorLibrary) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.variableGroup = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_variablegroup_info",
+			Help: "Azure DevOps library variable group",
+		},
+		[]string{
+			"projectID",
+			"variableGroupID",
+			"variableGroupName",
+			"type",
+			"keyVault",
+			"serviceEndpointID",
+			"isShared",
+			"createdBy",
+			"modifiedBy",
+		},
+	)
+	m.Collector.RegisterMetricList("variableGroup", m.prometheus.variableGroup, true)
+
+	m.prometheus.variableGroupStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_variablegroup_status",
+			Help: "Azure DevOps library variable group status (variables, secrets, authorized pipelines, created and modified time)",
+		},
+		[]string{
+			"projectID",
+			"variableGroupID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("variableGroupStatus", m.prometheus.variableGroupStatus, true)
+
+	m.prometheus.variableGroupPipeline = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_variablegroup_pipeline",
+			Help: "Azure DevOps library variable group authorized pipelines (authorization time)",
+		},
+		[]string{
+			"projectID",
+			"variableGroupID",
+			"pipelineID",
+			"authorizedBy",
+		},
+	)
+	m.Collector.RegisterMetricList("variableGroupPipeline", m.prometheus.variableGroupPipeline, true)
+
+	m.prometheus.variableGroupSecretExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_variablegroup_secret_expiry",
+			Help: "Azure DevOps library variable group key vault secret expiry",
+		},
+		[]string{
+			"projectID",
+			"variableGroupID",
+			"name",
+			"enabled",
+		},
+	)
+	m.Collector.RegisterMetricList("variableGroupSecretExpiry", m.prometheus.variableGroupSecretExpiry, true)
+
+	m.prometheus.secureFile = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_securefile_info",
+			Help: "Azure DevOps library secure file",
+		},
+		[]string{
+			"projectID",
+			"secureFileID",
+			"secureFileName",
+			"createdBy",
+			"modifiedBy",
+		},
+	)
+	m.Collector.RegisterMetricList("secureFile", m.prometheus.secureFile, true)
+
+	m.prometheus.secureFileStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_securefile_status",
+			Help: "Azure DevOps library secure file status (authorized pipelines, created and modified time)",
+		},
+		[]string{
+			"projectID",
+			"secureFileID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("secureFileStatus", m.prometheus.secureFileStatus, true)
+
+	m.prometheus.secureFilePipeline = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_securefile_pipeline",
+			Help: "Azure DevOps library secure file authorized pipelines (authorization time)",
+		},
+		[]string{
+			"projectID",
+			"secureFileID",
+			"pipelineID",
+			"authorizedBy",
+		},
+	)
+	m.Collector.RegisterMetricList("secureFilePipeline", m.prometheus.secureFilePipeline, true)
+}
+
+func (m *MetricsCollectorLibrary) Reset() {}
+
+func (m *MetricsCollectorLibrary) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectVariableGroups(ctx, projectLogger, callback, project)
+		m.collectSecureFiles(ctx, projectLogger, callback, project)
+	}
+}
+
+func (m *MetricsCollectorLibrary) collectVariableGroups(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListVariableGroups(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	variableGroupMetric := m.Collector.GetMetricList("variableGroup")
+	variableGroupStatusMetric := m.Collector.GetMetricList("variableGroupStatus")
+	variableGroupPipelineMetric := m.Collector.GetMetricList("variableGroupPipeline")
+	variableGroupSecretExpiryMetric := m.Collector.GetMetricList("variableGroupSecretExpiry")
+
+	for _, variableGroup := range list.List {
+		variableGroupId := int64ToString(variableGroup.Id)
+
+		keyVault := ""
+		serviceEndpointId := ""
+		if variableGroup.IsKeyVault() {
+			keyVault = variableGroup.ProviderData.Vault
+			serviceEndpointId = variableGroup.ProviderData.ServiceEndpointId
+		}
+
+		variableGroupMetric.AddInfo(prometheus.Labels{
+			"projectID":         project.Id,
+			"variableGroupID":   variableGroupId,
+			"variableGroupName": variableGroup.Name,
+			"type":              variableGroup.Type,
+			"keyVault":          keyVault,
+			"serviceEndpointID": serviceEndpointId,
+			"isShared":          to.BoolString(variableGroup.IsShared),
+			"createdBy":         variableGroup.CreatedBy.DisplayName,
+			"modifiedBy":        variableGroup.ModifiedBy.DisplayName,
+		})
+
+		statusLabels := func(statusType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":       project.Id,
+				"variableGroupID": variableGroupId,
+				"type":            statusType,
+			}
+		}
+
+		variableGroupStatusMetric.Add(statusLabels("variables"), float64(len(variableGroup.Variables)))
+		variableGroupStatusMetric.Add(statusLabels("secrets"), float64(variableGroup.SecretCount()))
+		variableGroupStatusMetric.AddTime(statusLabels("created"), variableGroup.CreatedOn)
+		variableGroupStatusMetric.AddTime(statusLabels("modified"), variableGroup.ModifiedOn)
+
+		if variableGroup.IsKeyVault() {
+			if variableGroup.ProviderData.LastRefreshedOn != nil {
+				variableGroupStatusMetric.AddTime(statusLabels("keyVaultRefreshed"), *variableGroup.ProviderData.LastRefreshedOn)
+			}
+
+			for name, variable := range variableGroup.Variables {
+				if variable.Expires == nil {
+					continue
+				}
+
+				variableGroupSecretExpiryMetric.AddTime(prometheus.Labels{
+					"projectID":       project.Id,
+					"variableGroupID": variableGroupId,
+					"name":            name,
+					"enabled":         to.BoolString(variable.Enabled == nil || *variable.Enabled),
+				}, *variable.Expires)
+			}
+		}
+
+		// authorized pipelines
+		permission, err := AzureDevopsClient.GetPipelinePermission(project.Id, "variablegroup", variableGroupId)
+		if err != nil {
+			logger.With(zap.String("variableGroup", variableGroup.Name)).Error(err)
+			continue
+		}
+
+		addPipelinePermissionMetrics(variableGroupPipelineMetric, variableGroupStatusMetric, statusLabels, prometheus.Labels{
+			"projectID":       project.Id,
+			"variableGroupID": variableGroupId,
+		}, permission)
+	}
+}
+
+func (m *MetricsCollectorLibrary) collectSecureFiles(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListSecureFiles(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	secureFileMetric := m.Collector.GetMetricList("secureFile")
+	secureFileStatusMetric := m.Collector.GetMetricList("secureFileStatus")
+	secureFilePipelineMetric := m.Collector.GetMetricList("secureFilePipeline")
+
+	for _, secureFile := range list.List {
+		secureFileMetric.AddInfo(prometheus.Labels{
+			"projectID":      project.Id,
+			"secureFileID":   secureFile.Id,
+			"secureFileName": secureFile.Name,
+			"createdBy":      secureFile.CreatedBy.DisplayName,
+			"modifiedBy":     secureFile.ModifiedBy.DisplayName,
+		})
+
+		statusLabels := func(statusType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":    project.Id,
+				"secureFileID": secureFile.Id,
+				"type":         statusType,
+			}
+		}
+
+		secureFileStatusMetric.AddTime(statusLabels("created"), secureFile.CreatedOn)
+		secureFileStatusMetric.AddTime(statusLabels("modified"), secureFile.ModifiedOn)
+
+		// authorized pipelines
+		permission, err := AzureDevopsClient.GetPipelinePermission(project.Id, "securefile", secureFile.Id)
+		if err != nil {
+			logger.With(zap.String("secureFile", secureFile.Name)).Error(err)
+			continue
+		}
+
+		addPipelinePermissionMetrics(secureFilePipelineMetric, secureFileStatusMetric, statusLabels, prometheus.Labels{
+			"projectID":    project.Id,
+			"secureFileID": secureFile.Id,
+		}, permission)
+	}
+}
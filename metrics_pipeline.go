@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorPipeline struct {
+	collector.Processor
+
+	prometheus struct {
+		pipeline                      *prometheus.GaugeVec
+		pipelineRun                   *prometheus.GaugeVec
+		pipelineRunStatus             *prometheus.GaugeVec
+		pipelineRunParameter          *prometheus.GaugeVec
+		pipelineRunResource           *prometheus.GaugeVec
+		pipelineEnvironment           *prometheus.GaugeVec
+		pipelineEnvironmentDeployment *prometheus.GaugeVec
+	}
+
+	// pipeline configurations (per revision) and finished runs don't change, so they are only fetched once
+	pipelineCache    map[string]devopsClient.Pipeline
+	pipelineRunCache map[string]devopsClient.PipelineRun
+}
+
+func (m *MetricsCollectorPipeline) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.pipelineCache = map[string]devopsClient.Pipeline{}
+	m.pipelineRunCache = map[string]devopsClient.PipelineRun{}
+
+	m.prometheus.pipeline = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_info",
+			Help: "Azure DevOps pipeline",
+		},
+		[]string{
+			"projectID",
+			"pipelineID",
+			"pipelineName",
+			"folder",
+			"configurationType",
+			"yamlPath",
+			"repositoryID",
+			"repositoryType",
+		},
+	)
+	m.Collector.RegisterMetricList("pipeline", m.prometheus.pipeline, true)
+
+	m.prometheus.pipelineRun = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_run_info",
+			Help: "Azure DevOps pipeline run",
+		},
+		[]string{
+			"projectID",
+			"pipelineID",
+			"runID",
+			"runName",
+			"state",
+			"result",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineRun", m.prometheus.pipelineRun, true)
+
+	m.prometheus.pipelineRunStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_run_status",
+			Help: "Azure DevOps pipeline run status",
+		},
+		[]string{
+			"projectID",
+			"pipelineID",
+			"runID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineRunStatus", m.prometheus.pipelineRunStatus, true)
+
+	m.prometheus.pipelineRunParameter = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_run_parameter",
+			Help: "Azure DevOps pipeline run template parameters (values only for pipeline.parameter-values)",
+		},
+		[]string{
+			"projectID",
+			"pipelineID",
+			"runID",
+			"name",
+			"value",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineRunParameter", m.prometheus.pipelineRunParameter, true)
+
+	m.prometheus.pipelineRunResource = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_run_resource",
+			Help: "Azure DevOps pipeline run resources (repositories, pipeline artifacts, containers)",
+		},
+		[]string{
+			"projectID",
+			"pipelineID",
+			"runID",
+			"resourceType",
+			"alias",
+			"name",
+			"version",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineRunResource", m.prometheus.pipelineRunResource, true)
+
+	m.prometheus.pipelineEnvironment = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_environment_info",
+			Help: "Azure DevOps pipeline environment",
+		},
+		[]string{
+			"projectID",
+			"environmentID",
+			"environmentName",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineEnvironment", m.prometheus.pipelineEnvironment, true)
+
+	m.prometheus.pipelineEnvironmentDeployment = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pipeline_environment_deployment",
+			Help: "Azure DevOps pipeline environment deployments of pipeline runs (finish time)",
+		},
+		[]string{
+			"projectID",
+			"environmentID",
+			"pipelineID",
+			"runID",
+			"stageName",
+			"jobName",
+			"result",
+		},
+	)
+	m.Collector.RegisterMetricList("pipelineEnvironmentDeployment", m.prometheus.pipelineEnvironmentDeployment, true)
+}
+
+func (m *MetricsCollectorPipeline) Reset() {}
+
+func (m *MetricsCollectorPipeline) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	cacheKeys := map[string]bool{}
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectPipelines(ctx, projectLogger, callback, project, cacheKeys)
+		m.collectEnvironments(ctx, projectLogger, callback, project)
+	}
+
+	// cleanup pipelines and runs which are no longer listed
+	for key := range m.pipelineCache {
+		if !cacheKeys[key] {
+			delete(m.pipelineCache, key)
+		}
+	}
+	for key := range m.pipelineRunCache {
+		if !cacheKeys[key] {
+			delete(m.pipelineRunCache, key)
+		}
+	}
+}
+
+func (m *MetricsCollectorPipeline) collectPipelines(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, cacheKeys map[string]bool) {
+	list, err := AzureDevopsClient.ListPipelines(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	pipelineMetric := m.Collector.GetMetricList("pipeline")
+
+	for _, row := range list.List {
+		pipelineLogger := logger.With(zap.Int64("pipelineId", row.Id))
+
+		// configuration (yaml path, repository) is only available for single pipelines
+		cacheKey := project.Id + "/" + int64ToString(row.Id)
+		cacheKeys[cacheKey] = true
+
+		pipeline, exists := m.pipelineCache[cacheKey]
+		if !exists || pipeline.Revision != row.Revision {
+			pipeline, err = AzureDevopsClient.GetPipeline(project.Id, row.Id)
+			if err != nil {
+				pipelineLogger.Error(err)
+				continue
+			}
+			m.pipelineCache[cacheKey] = pipeline
+		}
+
+		pipelineMetric.AddInfo(prometheus.Labels{
+			"projectID":         project.Id,
+			"pipelineID":        int64ToString(pipeline.Id),
+			"pipelineName":      pipeline.Name,
+			"folder":            pipeline.Folder,
+			"configurationType": pipeline.Configuration.Type,
+			"yamlPath":          pipeline.Configuration.Path,
+			"repositoryID":      pipeline.Configuration.Repository.Id,
+			"repositoryType":    pipeline.Configuration.Repository.Type,
+		})
+
+		m.collectPipelineRuns(ctx, pipelineLogger, callback, project, pipeline, cacheKeys)
+	}
+}
+
+func (m *MetricsCollectorPipeline) collectPipelineRuns(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, pipeline devopsClient.Pipeline, cacheKeys map[string]bool) {
+	minTime := time.Now().Add(-Opts.Limit.BuildHistoryDuration)
+
+	list, err := AzureDevopsClient.ListPipelineRuns(project.Id, pipeline.Id, minTime)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	pipelineRunMetric := m.Collector.GetMetricList("pipelineRun")
+	pipelineRunStatusMetric := m.Collector.GetMetricList("pipelineRunStatus")
+	pipelineRunParameterMetric := m.Collector.GetMetricList("pipelineRunParameter")
+	pipelineRunResourceMetric := m.Collector.GetMetricList("pipelineRunResource")
+
+	for _, row := range list.List {
+		// template parameters and resources are only available for single runs
+		cacheKey := project.Id + "/" + int64ToString(pipeline.Id) + "/" + int64ToString(row.Id)
+		cacheKeys[cacheKey] = true
+
+		run, exists := m.pipelineRunCache[cacheKey]
+		if !exists {
+			run, err = AzureDevopsClient.GetPipelineRun(project.Id, pipeline.Id, row.Id)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+
+			if run.State == "completed" {
+				m.pipelineRunCache[cacheKey] = run
+			}
+		}
+
+		pipelineRunMetric.AddInfo(prometheus.Labels{
+			"projectID":  project.Id,
+			"pipelineID": int64ToString(pipeline.Id),
+			"runID":      int64ToString(run.Id),
+			"runName":    run.Name,
+			"state":      run.State,
+			"result":     run.Result,
+		})
+
+		pipelineRunStatusMetric.AddTime(prometheus.Labels{
+			"projectID":  project.Id,
+			"pipelineID": int64ToString(pipeline.Id),
+			"runID":      int64ToString(run.Id),
+			"type":       "created",
+		}, run.CreatedDate)
+
+		if run.FinishedDate != nil {
+			pipelineRunStatusMetric.AddTime(prometheus.Labels{
+				"projectID":  project.Id,
+				"pipelineID": int64ToString(pipeline.Id),
+				"runID":      int64ToString(run.Id),
+				"type":       "finished",
+			}, *run.FinishedDate)
+
+			pipelineRunStatusMetric.AddDuration(prometheus.Labels{
+				"projectID":  project.Id,
+				"pipelineID": int64ToString(pipeline.Id),
+				"runID":      int64ToString(run.Id),
+				"type":       "duration",
+			}, run.FinishedDate.Sub(run.CreatedDate))
+		}
+
+		for name, value := range run.TemplateParameters {
+			// parameter values might contain free text or secrets, only export values of allowed parameters
+			parameterValue := ""
+			if arrayStringContains(Opts.AzureDevops.PipelineParameterValues, name) {
+				parameterValue = fmt.Sprintf("%v", value)
+			}
+
+			pipelineRunParameterMetric.AddInfo(prometheus.Labels{
+				"projectID":  project.Id,
+				"pipelineID": int64ToString(pipeline.Id),
+				"runID":      int64ToString(run.Id),
+				"name":       name,
+				"value":      parameterValue,
+			})
+		}
+
+		for _, resource := range run.Resources.List() {
+			pipelineRunResourceMetric.AddInfo(prometheus.Labels{
+				"projectID":    project.Id,
+				"pipelineID":   int64ToString(pipeline.Id),
+				"runID":        int64ToString(run.Id),
+				"resourceType": resource.Type,
+				"alias":        resource.Alias,
+				"name":         resource.Name,
+				"version":      resource.Version,
+			})
+		}
+	}
+}
+
+func (m *MetricsCollectorPipeline) collectEnvironments(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListEnvironments(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	minTime := time.Now().Add(-Opts.Limit.BuildHistoryDuration)
+
+	pipelineEnvironmentMetric := m.Collector.GetMetricList("pipelineEnvironment")
+	pipelineEnvironmentDeploymentMetric := m.Collector.GetMetricList("pipelineEnvironmentDeployment")
+
+	for _, environment := range list.List {
+		pipelineEnvironmentMetric.AddInfo(prometheus.Labels{
+			"projectID":       project.Id,
+			"environmentID":   int64ToString(environment.Id),
+			"environmentName": environment.Name,
+		})
+
+		recordList, err := AzureDevopsClient.ListEnvironmentDeploymentRecords(project.Id, environment.Id)
+		if err != nil {
+			logger.With(zap.String("environment", environment.Name)).Error(err)
+			continue
+		}
+
+		for _, record := range recordList.List {
+			if record.FinishTime == nil || record.FinishTime.Before(minTime) {
+				continue
+			}
+
+			pipelineEnvironmentDeploymentMetric.AddTime(prometheus.Labels{
+				"projectID":     project.Id,
+				"environmentID": int64ToString(environment.Id),
+				"pipelineID":    int64ToString(record.Definition.Id),
+				"runID":         int64ToString(record.Owner.Id),
+				"stageName":     record.StageName,
+				"jobName":       record.JobName,
+				"result":        record.Result,
+			}, *record.FinishTime)
+		}
+	}
+}
+
+// addPipelinePermissionMetrics exports the pipelines authorized to use a protected resource (endpoint, variable group, secure file)
+// and the authorization status (all pipelines authorized, number of authorized pipelines)
+func addPipelinePermissionMetrics(pipelineMetric, statusMetric *collector.MetricList, statusLabels func(statusType string) prometheus.Labels, resourceLabels prometheus.Labels, permission devopsClient.PipelinePermission) {
+	allPipelinesAuthorized := permission.AllPipelines != nil && permission.AllPipelines.Authorized
+	statusMetric.AddBool(statusLabels("allPipelinesAuthorized"), allPipelinesAuthorized)
+
+	authorizedPipelines := 0
+	for _, pipeline := range permission.Pipelines {
+		if !pipeline.Authorized {
+			continue
+		}
+		authorizedPipelines++
+
+		pipelineLabels := prometheus.Labels{
+			"pipelineID":   int64ToString(pipeline.Id),
+			"authorizedBy": pipeline.AuthorizedBy.DisplayName,
+		}
+		for name, value := range resourceLabels {
+			pipelineLabels[name] = value
+		}
+
+		if pipeline.AuthorizedOn != nil {
+			pipelineMetric.AddTime(pipelineLabels, *pipeline.AuthorizedOn)
+		} else {
+			pipelineMetric.AddInfo(pipelineLabels)
+		}
+	}
+	statusMetric.Add(statusLabels("authorizedPipelines"), float64(authorizedPipelines))
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorPolicy struct {
+	collector.Processor
+
+	prometheus struct {
+		policy           *prometheus.GaugeVec
+		policySetting    *prometheus.GaugeVec
+		policyCompliance *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorPolicy) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.policy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_policy_info",
+			Help: "Azure DevOps branch policy",
+		},
+		[]string{
+			"projectID",
+			"policyID",
+			"policyType",
+			"policyTypeName",
+			"repositoryID",
+			"branchName",
+			"matchKind",
+			"isEnabled",
+			"isBlocking",
+		},
+	)
+	m.Collector.RegisterMetricList("policy", m.prometheus.policy, true)
+
+	m.prometheus.policySetting = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_policy_setting",
+			Help: "Azure DevOps branch policy settings",
+		},
+		[]string{
+			"projectID",
+			"policyID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("policySetting", m.prometheus.policySetting, true)
+
+	m.prometheus.policyCompliance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_policy_compliance",
+			Help: "Azure DevOps branch policy compliance of repository default branches (required policy enabled and blocking)",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"branchName",
+			"policyType",
+		},
+	)
+	m.Collector.RegisterMetricList("policyCompliance", m.prometheus.policyCompliance, true)
+}
+
+func (m *MetricsCollectorPolicy) Reset() {}
+
+func (m *MetricsCollectorPolicy) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectPolicies(ctx, projectLogger, callback, project)
+	}
+}
+
+func (m *MetricsCollectorPolicy) collectPolicies(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListPolicyConfigurations(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	policyMetric := m.Collector.GetMetricList("policy")
+	policySettingMetric := m.Collector.GetMetricList("policySetting")
+	policyComplianceMetric := m.Collector.GetMetricList("policyCompliance")
+
+	for _, policy := range list.List {
+		if policy.IsDeleted {
+			continue
+		}
+
+		for _, scope := range policy.Settings.Scope {
+			policyMetric.AddInfo(prometheus.Labels{
+				"projectID":      project.Id,
+				"policyID":       int64ToString(policy.Id),
+				"policyType":     policy.TypeKey(),
+				"policyTypeName": policy.Type.DisplayName,
+				"repositoryID":   scope.RepositoryIdString(),
+				"branchName":     scope.BranchNameString(),
+				"matchKind":      scope.MatchKindString(),
+				"isEnabled":      to.BoolString(policy.IsEnabled),
+				"isBlocking":     to.BoolString(policy.IsBlocking),
+			})
+		}
+
+		settings := policy.Settings
+		settingLabels := func(settingType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID": project.Id,
+				"policyID":  int64ToString(policy.Id),
+				"type":      settingType,
+			}
+		}
+
+		policySettingMetric.AddIfNotNil(settingLabels("minimumApproverCount"), settings.MinimumApproverCount)
+		policySettingMetric.AddIfNotNil(settingLabels("validDuration"), settings.ValidDuration)
+		if settings.BuildDefinitionId != nil {
+			policySettingMetric.Add(settingLabels("buildDefinitionID"), float64(*settings.BuildDefinitionId))
+		}
+		if settings.CreatorVoteCounts != nil {
+			policySettingMetric.AddBool(settingLabels("creatorVoteCounts"), *settings.CreatorVoteCounts)
+		}
+		if settings.ResetOnSourcePush != nil {
+			policySettingMetric.AddBool(settingLabels("resetOnSourcePush"), *settings.ResetOnSourcePush)
+		}
+		if settings.AllowNoFastForward != nil {
+			policySettingMetric.AddBool(settingLabels("allowNoFastForward"), *settings.AllowNoFastForward)
+		}
+		if settings.AllowSquash != nil {
+			policySettingMetric.AddBool(settingLabels("allowSquash"), *settings.AllowSquash)
+		}
+		if settings.AllowRebase != nil {
+			policySettingMetric.AddBool(settingLabels("allowRebase"), *settings.AllowRebase)
+		}
+		if settings.AllowRebaseMerge != nil {
+			policySettingMetric.AddBool(settingLabels("allowRebaseMerge"), *settings.AllowRebaseMerge)
+		}
+	}
+
+	// compliance of default branches against required policy set
+	for _, repository := range project.RepositoryList.List {
+		if repository.Disabled() || repository.DefaultBranch == "" {
+			continue
+		}
+
+		for _, requiredPolicyType := range Opts.AzureDevops.PolicyRequired {
+			compliant := false
+			for _, policy := range list.List {
+				if policy.IsDeleted || !policy.IsEnabled || !policy.IsBlocking {
+					continue
+				}
+
+				if policy.TypeKey() == requiredPolicyType && policy.AppliesTo(repository, repository.DefaultBranch) {
+					compliant = true
+					break
+				}
+			}
+
+			policyComplianceMetric.AddBool(prometheus.Labels{
+				"projectID":    project.Id,
+				"repositoryID": repository.Id,
+				"branchName":   repository.DefaultBranchName(),
+				"policyType":   requiredPolicyType,
+			}, compliant)
+		}
+	}
+}
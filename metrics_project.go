@@ -14,14 +14,20 @@ type MetricsCollectorProject struct {
 	collector.Processor
 
 	prometheus struct {
-		project    *prometheus.GaugeVec
-		repository *prometheus.GaugeVec
+		project       *prometheus.GaugeVec
+		projectStatus *prometheus.GaugeVec
+		repository    *prometheus.GaugeVec
 	}
+
+	// last successfully fetched project details (keeps info labels stable if details cannot be fetched)
+	projectDetails map[string]devopsClient.Project
 }
 
 func (m *MetricsCollectorProject) Setup(collector *collector.Collector) {
 	m.Processor.Setup(collector)
 
+	m.projectDetails = map[string]devopsClient.Project{}
+
 	m.prometheus.project = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_project_info",
@@ -30,9 +36,26 @@ func (m *MetricsCollectorProject) Setup(collector *collector.Collector) {
 		[]string{
 			"projectID",
 			"projectName",
+			"state",
+			"visibility",
+			"processTemplate",
+			"defaultTeamID",
+			"defaultTeamName",
 		},
 	)
 	m.Collector.RegisterMetricList("project", m.prometheus.project, true)
+
+	m.prometheus.projectStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_project_status",
+			Help: "Azure DevOps project status (revision and last update time)",
+		},
+		[]string{
+			"projectID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("projectStatus", m.prometheus.projectStatus, true)
 }
 
 func (m *MetricsCollectorProject) Reset() {}
@@ -49,9 +72,49 @@ func (m *MetricsCollectorProject) Collect(callback chan<- func()) {
 
 func (m *MetricsCollectorProject) collectProject(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
 	projectMetric := m.Collector.GetMetricList("project")
+	projectStatusMetric := m.Collector.GetMetricList("projectStatus")
+
+	// process template and default team are only available in project details
+	details, err := AzureDevopsClient.GetProject(project.Id)
+	if err == nil {
+		m.projectDetails[project.Id] = details
+	} else {
+		logger.Error(err)
+
+		// use previous details or listed project (without process template and default team)
+		if val, exists := m.projectDetails[project.Id]; exists {
+			details = val
+		} else {
+			details = project
+		}
+	}
+
+	defaultTeamId := ""
+	defaultTeamName := ""
+	if details.DefaultTeam != nil {
+		defaultTeamId = details.DefaultTeam.Id
+		defaultTeamName = details.DefaultTeam.Name
+	}
 
 	projectMetric.AddInfo(prometheus.Labels{
-		"projectID":   project.Id,
-		"projectName": project.Name,
+		"projectID":       project.Id,
+		"projectName":     project.Name,
+		"state":           details.State,
+		"visibility":      details.Visibility,
+		"processTemplate": details.Capabilities.ProcessTemplate.TemplateName,
+		"defaultTeamID":   defaultTeamId,
+		"defaultTeamName": defaultTeamName,
 	})
+
+	projectStatusMetric.Add(prometheus.Labels{
+		"projectID": project.Id,
+		"type":      "revision",
+	}, float64(details.Revision))
+
+	if details.LastUpdateTime != nil {
+		projectStatusMetric.AddTime(prometheus.Labels{
+			"projectID": project.Id,
+			"type":      "lastUpdate",
+		}, *details.LastUpdateTime)
+	}
 }
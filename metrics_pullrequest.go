@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/webdevops/go-common/prometheus/collector"
@@ -15,15 +17,33 @@ type MetricsCollectorPullRequest struct {
 	collector.Processor
 
 	prometheus struct {
-		pullRequest       *prometheus.GaugeVec
-		pullRequestStatus *prometheus.GaugeVec
-		pullRequestLabel  *prometheus.GaugeVec
+		pullRequest                *prometheus.GaugeVec
+		pullRequestStatus          *prometheus.GaugeVec
+		pullRequestLabel           *prometheus.GaugeVec
+		pullRequestStats           *prometheus.GaugeVec
+		pullRequestThreads         *prometheus.GaugeVec
+		pullRequestReviewer        *prometheus.GaugeVec
+		pullRequestReviewerPending *prometheus.GaugeVec
+		pullRequestReviewerWaiting *prometheus.GaugeVec
+		pullRequestReviewerMissing *prometheus.GaugeVec
+		pullRequestDuration        *prometheus.HistogramVec
 	}
+
+	// details of closed pullrequests (do not change after closing), only fetched once per pullrequest
+	closedPullRequestDetails map[string]pullRequestDetails
+}
+
+// pullRequestDetails are the iterations (pushes) and comment threads of a pullrequest
+type pullRequestDetails struct {
+	Iterations *devopsClient.PullRequestIterationList
+	Threads    *devopsClient.PullRequestThreadList
 }
 
 func (m *MetricsCollectorPullRequest) Setup(collector *collector.Collector) {
 	m.Processor.Setup(collector)
 
+	m.closedPullRequestDetails = map[string]pullRequestDetails{}
+
 	m.prometheus.pullRequest = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_pullrequest_info",
@@ -72,6 +92,125 @@ func (m *MetricsCollectorPullRequest) Setup(collector *collector.Collector) {
 		},
 	)
 	m.Collector.RegisterMetricList("pullRequestLabel", m.prometheus.pullRequestLabel, true)
+
+	m.prometheus.pullRequestStats = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_stats",
+			Help: "Azure DevOps pullrequest stats",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"pullrequestID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestStats", m.prometheus.pullRequestStats, true)
+
+	m.prometheus.pullRequestThreads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_threads",
+			Help: "Azure DevOps pullrequest comment threads by status",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"pullrequestID",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestThreads", m.prometheus.pullRequestThreads, true)
+
+	m.prometheus.pullRequestReviewer = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_reviewer_vote",
+			Help: "Azure DevOps pullrequest reviewer vote (10 approved, 5 approved with suggestions, 0 no vote, -5 waiting for author, -10 rejected)",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"pullrequestID",
+			"reviewerID",
+			"reviewer",
+			"reviewerType",
+			"isRequired",
+			"isFlagged",
+			"hasDeclined",
+			"votedFor",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestReviewer", m.prometheus.pullRequestReviewer, true)
+
+	m.prometheus.pullRequestReviewerPending = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_reviewer_pending",
+			Help: "Azure DevOps number of active pullrequests without vote per reviewer",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"reviewerID",
+			"reviewer",
+			"reviewerType",
+			"isRequired",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestReviewerPending", m.prometheus.pullRequestReviewerPending, true)
+
+	m.prometheus.pullRequestReviewerWaiting = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_reviewer_waiting",
+			Help: "Azure DevOps waiting time (seconds since creation) of active pullrequests without vote of the reviewer",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"pullrequestID",
+			"reviewerID",
+			"reviewer",
+			"reviewerType",
+			"isRequired",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestReviewerWaiting", m.prometheus.pullRequestReviewerWaiting, true)
+
+	m.prometheus.pullRequestReviewerMissing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_pullrequest_reviewer_required",
+			Help: "Azure DevOps number of required reviewers without vote on active pullrequests",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"pullrequestID",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestReviewerMissing", m.prometheus.pullRequestReviewerMissing, true)
+
+	m.prometheus.pullRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "azure_devops_pullrequest_duration",
+			Help: "Azure DevOps pullrequest lifecycle durations (seconds from creation to first review, approval and merge) of completed pullrequests",
+			Buckets: []float64{
+				15 * 60,           // 15m
+				1 * 60 * 60,       // 1h
+				4 * 60 * 60,       // 4h
+				12 * 60 * 60,      // 12h
+				1 * 24 * 60 * 60,  // 1d
+				2 * 24 * 60 * 60,  // 2d
+				4 * 24 * 60 * 60,  // 4d
+				7 * 24 * 60 * 60,  // 1w
+				14 * 24 * 60 * 60, // 2w
+				28 * 24 * 60 * 60, // 4w
+			},
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("pullRequestDuration", m.prometheus.pullRequestDuration, false)
 }
 
 func (m *MetricsCollectorPullRequest) Reset() {}
@@ -80,6 +219,7 @@ func (m *MetricsCollectorPullRequest) Collect(callback chan<- func()) {
 	ctx := m.Context()
 	logger := m.Logger()
 
+	closedPullRequests := map[string]bool{}
 	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
 		projectLogger := logger.With(zap.String("project", project.Name))
 
@@ -90,6 +230,14 @@ func (m *MetricsCollectorPullRequest) Collect(callback chan<- func()) {
 
 			repoLogger := projectLogger.With(zap.String("repository", repository.Name))
 			m.collectPullRequests(ctx, repoLogger, callback, project, repository)
+			m.collectClosedPullRequests(ctx, repoLogger, callback, project, repository, closedPullRequests)
+		}
+	}
+
+	// cleanup details of pullrequests which are no longer in the history
+	for key := range m.closedPullRequestDetails {
+		if !closedPullRequests[key] {
+			delete(m.closedPullRequestDetails, key)
 		}
 	}
 }
@@ -101,41 +249,265 @@ func (m *MetricsCollectorPullRequest) collectPullRequests(ctx context.Context, l
 		return
 	}
 
+	for _, pullRequest := range list.List {
+		details := m.fetchPullRequestDetails(logger, project, repository, pullRequest)
+		m.collectPullRequest(ctx, logger, callback, project, repository, pullRequest, details)
+	}
+
+	m.collectReviewers(project, repository, list)
+}
+
+func (m *MetricsCollectorPullRequest) collectReviewers(project devopsClient.Project, repository devopsClient.Repository, list devopsClient.PullRequestList) {
+	pullRequestReviewerMetric := m.Collector.GetMetricList("pullRequestReviewer")
+	pullRequestReviewerPendingMetric := m.Collector.GetMetricList("pullRequestReviewerPending")
+	pullRequestReviewerWaitingMetric := m.Collector.GetMetricList("pullRequestReviewerWaiting")
+	pullRequestReviewerMissingMetric := m.Collector.GetMetricList("pullRequestReviewerMissing")
+
+	type reviewerPendingKey struct {
+		Id         string
+		Name       string
+		Type       string
+		IsRequired bool
+	}
+
+	reviewerPendingCount := map[reviewerPendingKey]int64{}
+	for _, pullRequest := range list.List {
+		if pullRequest.IsDraft {
+			continue
+		}
+
+		requiredMissing := int64(0)
+		for _, reviewer := range pullRequest.Reviewers {
+			pullRequestReviewerMetric.Add(prometheus.Labels{
+				"projectID":     project.Id,
+				"repositoryID":  repository.Id,
+				"pullrequestID": int64ToString(pullRequest.Id),
+				"reviewerID":    reviewer.Id,
+				"reviewer":      reviewer.DisplayName,
+				"reviewerType":  reviewer.Type(),
+				"isRequired":    to.BoolString(reviewer.IsRequired),
+				"isFlagged":     to.BoolString(reviewer.IsFlagged),
+				"hasDeclined":   to.BoolString(reviewer.HasDeclined),
+				"votedFor":      strings.Join(reviewer.Groups(), ","),
+			}, float64(reviewer.Vote))
+
+			if !reviewer.IsPending() {
+				continue
+			}
+
+			if reviewer.IsRequired {
+				requiredMissing++
+			}
+
+			reviewerPendingCount[reviewerPendingKey{
+				Id:         reviewer.Id,
+				Name:       reviewer.DisplayName,
+				Type:       reviewer.Type(),
+				IsRequired: reviewer.IsRequired,
+			}]++
+
+			pullRequestReviewerWaitingMetric.AddDuration(prometheus.Labels{
+				"projectID":     project.Id,
+				"repositoryID":  repository.Id,
+				"pullrequestID": int64ToString(pullRequest.Id),
+				"reviewerID":    reviewer.Id,
+				"reviewer":      reviewer.DisplayName,
+				"reviewerType":  reviewer.Type(),
+				"isRequired":    to.BoolString(reviewer.IsRequired),
+			}, time.Since(pullRequest.CreationDate))
+		}
+
+		pullRequestReviewerMissingMetric.Add(prometheus.Labels{
+			"projectID":     project.Id,
+			"repositoryID":  repository.Id,
+			"pullrequestID": int64ToString(pullRequest.Id),
+		}, float64(requiredMissing))
+	}
+
+	for reviewer, count := range reviewerPendingCount {
+		pullRequestReviewerPendingMetric.Add(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"reviewerID":   reviewer.Id,
+			"reviewer":     reviewer.Name,
+			"reviewerType": reviewer.Type,
+			"isRequired":   to.BoolString(reviewer.IsRequired),
+		}, float64(count))
+	}
+}
+
+func (m *MetricsCollectorPullRequest) collectClosedPullRequests(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, repository devopsClient.Repository, closedPullRequests map[string]bool) {
+	minTime := time.Now().Add(-Opts.Limit.PullRequestHistoryDuration)
+
+	// lifecycle durations are only observed once (for pullrequests completed since last run)
+	durationMinTime := time.Now().Add(-*m.Collector.GetScapeTime())
+	if val := m.Collector.GetLastScapeTime(); val != nil {
+		durationMinTime = *val
+	}
+
+	pullRequestStatusMetric := m.Collector.GetMetricList("pullRequestStatus")
+	pullRequestDurationMetric := m.Collector.GetMetricList("pullRequestDuration")
+
+	for _, status := range []string{"completed", "abandoned"} {
+		list, err := AzureDevopsClient.ListPullrequestHistory(project.Id, repository.Id, status, minTime)
+		if err != nil {
+			logger.With(zap.String("status", status)).Error(err)
+			continue
+		}
+
+		for _, pullRequest := range list.List {
+			key := repository.Id + "/" + int64ToString(pullRequest.Id)
+			closedPullRequests[key] = true
+
+			details, exists := m.closedPullRequestDetails[key]
+			if !exists {
+				details = m.fetchPullRequestDetails(logger, project, repository, pullRequest)
+				if details.Iterations != nil && details.Threads != nil {
+					m.closedPullRequestDetails[key] = details
+				}
+			}
+
+			m.collectPullRequest(ctx, logger, callback, project, repository, pullRequest, details)
+
+			pullRequestStatusMetric.AddTime(prometheus.Labels{
+				"projectID":     project.Id,
+				"repositoryID":  repository.Id,
+				"pullrequestID": int64ToString(pullRequest.Id),
+				"type":          "closed",
+			}, pullRequest.ClosedDate)
+
+			if status != "completed" || !pullRequest.ClosedDate.After(durationMinTime) {
+				continue
+			}
+
+			pullRequestDurationMetric.AddDuration(prometheus.Labels{
+				"projectID":    project.Id,
+				"repositoryID": repository.Id,
+				"type":         "merge",
+			}, pullRequest.ClosedDate.Sub(pullRequest.CreationDate))
+
+			threadList := devopsClient.PullRequestThreadList{}
+			if details.Threads != nil {
+				threadList = *details.Threads
+			}
+
+			if firstReview := pullRequest.FirstReviewTime(threadList); firstReview != nil {
+				pullRequestDurationMetric.AddDuration(prometheus.Labels{
+					"projectID":    project.Id,
+					"repositoryID": repository.Id,
+					"type":         "firstReview",
+				}, firstReview.Sub(pullRequest.CreationDate))
+			}
+
+			if firstApproval := pullRequest.FirstApprovalTime(threadList); firstApproval != nil {
+				pullRequestDurationMetric.AddDuration(prometheus.Labels{
+					"projectID":    project.Id,
+					"repositoryID": repository.Id,
+					"type":         "approval",
+				}, firstApproval.Sub(pullRequest.CreationDate))
+			}
+		}
+	}
+}
+
+// fetchPullRequestDetails fetches iterations and comment threads of a pullrequest (nil if not available)
+func (m *MetricsCollectorPullRequest) fetchPullRequestDetails(logger *zap.SugaredLogger, project devopsClient.Project, repository devopsClient.Repository, pullRequest devopsClient.PullRequest) (details pullRequestDetails) {
+	if iterationList, err := AzureDevopsClient.ListPullrequestIterations(project.Id, repository.Id, pullRequest.Id); err == nil {
+		details.Iterations = &iterationList
+	} else {
+		logger.Error(err)
+	}
+
+	if threadList, err := AzureDevopsClient.ListPullrequestThreads(project.Id, repository.Id, pullRequest.Id); err == nil {
+		details.Threads = &threadList
+	} else {
+		logger.Error(err)
+	}
+
+	return
+}
+
+func (m *MetricsCollectorPullRequest) collectPullRequest(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, repository devopsClient.Repository, pullRequest devopsClient.PullRequest, details pullRequestDetails) {
 	pullRequestMetric := m.Collector.GetMetricList("pullRequest")
 	pullRequestStatusMetric := m.Collector.GetMetricList("pullRequestStatus")
 	pullRequestLabelMetric := m.Collector.GetMetricList("pullRequestLabel")
+	pullRequestStatsMetric := m.Collector.GetMetricList("pullRequestStats")
+	pullRequestThreadsMetric := m.Collector.GetMetricList("pullRequestThreads")
 
-	for _, pullRequest := range list.List {
-		voteSummary := pullRequest.GetVoteSummary()
-
-		pullRequestMetric.AddInfo(prometheus.Labels{
-			"projectID":        project.Id,
-			"repositoryID":     repository.Id,
-			"pullrequestID":    int64ToString(pullRequest.Id),
-			"pullrequestTitle": pullRequest.Title,
-			"status":           pullRequest.Status,
-			"voteStatus":       voteSummary.HumanizeString(),
-			"creator":          pullRequest.CreatedBy.DisplayName,
-			"isDraft":          to.BoolString(pullRequest.IsDraft),
-			"sourceBranch":     pullRequest.SourceRefName,
-			"targetBranch":     pullRequest.TargetRefName,
+	voteSummary := pullRequest.GetVoteSummary()
+
+	pullRequestMetric.AddInfo(prometheus.Labels{
+		"projectID":        project.Id,
+		"repositoryID":     repository.Id,
+		"pullrequestID":    int64ToString(pullRequest.Id),
+		"pullrequestTitle": pullRequest.Title,
+		"status":           pullRequest.Status,
+		"voteStatus":       voteSummary.HumanizeString(),
+		"creator":          pullRequest.CreatedBy.DisplayName,
+		"isDraft":          to.BoolString(pullRequest.IsDraft),
+		"sourceBranch":     pullRequest.SourceRefName,
+		"targetBranch":     pullRequest.TargetRefName,
+	})
+
+	pullRequestStatusMetric.AddTime(prometheus.Labels{
+		"projectID":     project.Id,
+		"repositoryID":  repository.Id,
+		"pullrequestID": int64ToString(pullRequest.Id),
+		"type":          "created",
+	}, pullRequest.CreationDate)
+
+	for _, label := range pullRequest.Labels {
+		pullRequestLabelMetric.AddInfo(prometheus.Labels{
+			"projectID":     project.Id,
+			"repositoryID":  repository.Id,
+			"pullrequestID": int64ToString(pullRequest.Id),
+			"label":         label.Name,
+			"active":        to.BoolString(label.Active),
 		})
+	}
+
+	// iterations (pushes)
+	if details.Iterations != nil {
+		pullRequestStatsMetric.Add(prometheus.Labels{
+			"projectID":     project.Id,
+			"repositoryID":  repository.Id,
+			"pullrequestID": int64ToString(pullRequest.Id),
+			"type":          "iterations",
+		}, float64(len(details.Iterations.List)))
+	}
+
+	// comment threads
+	if details.Threads != nil {
+		threadCount := 0
+		threadStatusCount := map[string]int64{}
+		for _, thread := range details.Threads.List {
+			if !thread.IsCommentThread() {
+				continue
+			}
+
+			threadCount++
+
+			status := thread.Status
+			if status == "" {
+				status = "unknown"
+			}
+			threadStatusCount[status]++
+		}
 
-		pullRequestStatusMetric.AddTime(prometheus.Labels{
+		pullRequestStatsMetric.Add(prometheus.Labels{
 			"projectID":     project.Id,
 			"repositoryID":  repository.Id,
 			"pullrequestID": int64ToString(pullRequest.Id),
-			"type":          "created",
-		}, pullRequest.CreationDate)
+			"type":          "threads",
+		}, float64(threadCount))
 
-		for _, label := range pullRequest.Labels {
-			pullRequestLabelMetric.AddInfo(prometheus.Labels{
+		for status, count := range threadStatusCount {
+			pullRequestThreadsMetric.Add(prometheus.Labels{
 				"projectID":     project.Id,
 				"repositoryID":  repository.Id,
 				"pullrequestID": int64ToString(pullRequest.Id),
-				"label":         label.Name,
-				"active":        to.BoolString(label.Active),
-			})
+				"status":        status,
+			}, float64(count))
 		}
 	}
 }
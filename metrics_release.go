@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,9 +23,16 @@ type MetricsCollectorRelease struct {
 		releaseEnvironment         *prometheus.GaugeVec
 		releaseEnvironmentApproval *prometheus.GaugeVec
 		releaseEnvironmentStatus   *prometheus.GaugeVec
+		releaseGate                *prometheus.GaugeVec
+		releaseGateEvaluation      *prometheus.GaugeVec
+		releaseGateDuration        *prometheus.GaugeVec
 
 		releaseDefinition            *prometheus.GaugeVec
 		releaseDefinitionEnvironment *prometheus.GaugeVec
+		releaseDefinitionGate        *prometheus.GaugeVec
+		releaseDefinitionGateOptions *prometheus.GaugeVec
+		releaseDefinitionCondition   *prometheus.GaugeVec
+		releaseDefinitionSchedule    *prometheus.GaugeVec
 	}
 }
 
@@ -123,6 +132,55 @@ func (m *MetricsCollectorRelease) Setup(collector *collector.Collector) {
 	)
 	m.Collector.RegisterMetricList("releaseEnvironmentApproval", m.prometheus.releaseEnvironmentApproval, true)
 
+	m.prometheus.releaseGate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_gate_info",
+			Help: "Azure DevOps release environment gates (latest deployment attempt)",
+		},
+		[]string{
+			"projectID",
+			"releaseID",
+			"releaseDefinitionID",
+			"environmentID",
+			"stage",
+			"status",
+			"ignoredGates",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseGate", m.prometheus.releaseGate, true)
+
+	m.prometheus.releaseGateEvaluation = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_gate_evaluation",
+			Help: "Azure DevOps release environment gate evaluations per gate and result (latest deployment attempt)",
+		},
+		[]string{
+			"projectID",
+			"releaseID",
+			"releaseDefinitionID",
+			"environmentID",
+			"stage",
+			"gateName",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseGateEvaluation", m.prometheus.releaseGateEvaluation, true)
+
+	m.prometheus.releaseGateDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_gate_duration",
+			Help: "Azure DevOps release environment gate wait duration (latest deployment attempt)",
+		},
+		[]string{
+			"projectID",
+			"releaseID",
+			"releaseDefinitionID",
+			"environmentID",
+			"stage",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseGateDuration", m.prometheus.releaseGateDuration, true)
+
 	m.prometheus.releaseDefinition = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "azure_devops_release_definition_info",
@@ -156,6 +214,71 @@ func (m *MetricsCollectorRelease) Setup(collector *collector.Collector) {
 		},
 	)
 	m.Collector.RegisterMetricList("releaseDefinitionEnvironment", m.prometheus.releaseDefinitionEnvironment, true)
+
+	m.prometheus.releaseDefinitionGate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_definition_gate",
+			Help: "Azure DevOps release definition environment pre and post deployment gates",
+		},
+		[]string{
+			"projectID",
+			"releaseDefinitionID",
+			"environmentID",
+			"stage",
+			"gateName",
+			"taskID",
+			"enabled",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseDefinitionGate", m.prometheus.releaseDefinitionGate, true)
+
+	m.prometheus.releaseDefinitionGateOptions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_definition_gate_options",
+			Help: "Azure DevOps release definition environment gate options (timeout, sampling interval, stabilization time, minimum success duration)",
+		},
+		[]string{
+			"projectID",
+			"releaseDefinitionID",
+			"environmentID",
+			"stage",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseDefinitionGateOptions", m.prometheus.releaseDefinitionGateOptions, true)
+
+	m.prometheus.releaseDefinitionCondition = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_definition_condition",
+			Help: "Azure DevOps release definition environment deployment conditions",
+		},
+		[]string{
+			"projectID",
+			"releaseDefinitionID",
+			"environmentID",
+			"conditionType",
+			"name",
+			"value",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseDefinitionCondition", m.prometheus.releaseDefinitionCondition, true)
+
+	m.prometheus.releaseDefinitionSchedule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_release_definition_schedule",
+			Help: "Azure DevOps release definition environment scheduled deployment triggers",
+		},
+		[]string{
+			"projectID",
+			"releaseDefinitionID",
+			"environmentID",
+			"daysToRelease",
+			"startTime",
+			"timeZone",
+			"onlyWithChanges",
+		},
+	)
+	m.Collector.RegisterMetricList("releaseDefinitionSchedule", m.prometheus.releaseDefinitionSchedule, true)
 }
 
 func (m *MetricsCollectorRelease) Reset() {}
@@ -179,6 +302,8 @@ func (m *MetricsCollectorRelease) collectReleases(ctx context.Context, logger *z
 
 	releaseDefinitionMetric := m.Collector.GetMetricList("releaseDefinition")
 	releaseDefinitionEnvironmentMetric := m.Collector.GetMetricList("releaseDefinitionEnvironment")
+	releaseDefinitionConditionMetric := m.Collector.GetMetricList("releaseDefinitionCondition")
+	releaseDefinitionScheduleMetric := m.Collector.GetMetricList("releaseDefinitionSchedule")
 
 	releaseMetric := m.Collector.GetMetricList("release")
 	releaseArtifactMetric := m.Collector.GetMetricList("releaseArtifact")
@@ -209,6 +334,32 @@ func (m *MetricsCollectorRelease) collectReleases(ctx context.Context, logger *z
 				"releaseID":           int64ToString(environment.CurrentRelease.Id),
 				"badgeUrl":            environment.BadgeUrl,
 			})
+
+			m.collectReleaseDefinitionGates(project, releaseDefinition, environment, "preDeployment", environment.PreDeploymentGates)
+			m.collectReleaseDefinitionGates(project, releaseDefinition, environment, "postDeployment", environment.PostDeploymentGates)
+
+			for _, condition := range environment.Conditions {
+				releaseDefinitionConditionMetric.AddInfo(prometheus.Labels{
+					"projectID":           project.Id,
+					"releaseDefinitionID": int64ToString(releaseDefinition.Id),
+					"environmentID":       int64ToString(environment.Id),
+					"conditionType":       condition.ConditionType,
+					"name":                condition.Name,
+					"value":               condition.Value,
+				})
+			}
+
+			for _, schedule := range environment.Schedules {
+				releaseDefinitionScheduleMetric.AddInfo(prometheus.Labels{
+					"projectID":           project.Id,
+					"releaseDefinitionID": int64ToString(releaseDefinition.Id),
+					"environmentID":       int64ToString(environment.Id),
+					"daysToRelease":       schedule.DaysToRelease,
+					"startTime":           fmt.Sprintf("%02d:%02d", schedule.StartHours, schedule.StartMinutes),
+					"timeZone":            schedule.TimeZoneId,
+					"onlyWithChanges":     to.BoolString(schedule.ScheduleOnlyWithChanges),
+				})
+			}
 		}
 	}
 
@@ -285,6 +436,19 @@ func (m *MetricsCollectorRelease) collectReleases(ctx context.Context, logger *z
 				"type":                "jobDuration",
 			}, environment.TimeToDeploy*60)
 
+			releaseEnvironmentStatusMetric.AddIfGreaterZero(prometheus.Labels{
+				"projectID":           project.Id,
+				"releaseID":           int64ToString(release.Id),
+				"releaseDefinitionID": int64ToString(release.Definition.Id),
+				"environmentID":       int64ToString(environment.DefinitionEnvironmentId),
+				"type":                "retries",
+			}, float64(len(environment.DeploySteps)-1))
+
+			if deployStep := environment.LatestDeployStep(); deployStep != nil {
+				m.collectReleaseGates(project, release, environment, "preDeployment", deployStep.PreDeploymentGates)
+				m.collectReleaseGates(project, release, environment, "postDeployment", deployStep.PostDeploymentGates)
+			}
+
 			for _, approval := range environment.PreDeployApprovals {
 				// skip automated approvals
 				if approval.IsAutomated {
@@ -331,3 +495,102 @@ func (m *MetricsCollectorRelease) collectReleases(ctx context.Context, logger *z
 		}
 	}
 }
+
+func (m *MetricsCollectorRelease) collectReleaseDefinitionGates(project devopsClient.Project, releaseDefinition devopsClient.ReleaseDefinition, environment devopsClient.ReleaseDefinitionEnvironment, stage string, gates devopsClient.ReleaseDefinitionGates) {
+	if !gates.IsEnabled() {
+		return
+	}
+
+	releaseDefinitionGateMetric := m.Collector.GetMetricList("releaseDefinitionGate")
+	releaseDefinitionGateOptionsMetric := m.Collector.GetMetricList("releaseDefinitionGateOptions")
+
+	for _, gate := range gates.Gates {
+		for _, task := range gate.Tasks {
+			releaseDefinitionGateMetric.AddInfo(prometheus.Labels{
+				"projectID":           project.Id,
+				"releaseDefinitionID": int64ToString(releaseDefinition.Id),
+				"environmentID":       int64ToString(environment.Id),
+				"stage":               stage,
+				"gateName":            task.Name,
+				"taskID":              task.TaskId,
+				"enabled":             to.BoolString(task.Enabled),
+			})
+		}
+	}
+
+	optionLabels := func(optionType string) prometheus.Labels {
+		return prometheus.Labels{
+			"projectID":           project.Id,
+			"releaseDefinitionID": int64ToString(releaseDefinition.Id),
+			"environmentID":       int64ToString(environment.Id),
+			"stage":               stage,
+			"type":                optionType,
+		}
+	}
+
+	// gate options are configured in minutes
+	releaseDefinitionGateOptionsMetric.AddDuration(optionLabels("timeout"), time.Duration(gates.GatesOptions.Timeout)*time.Minute)
+	releaseDefinitionGateOptionsMetric.AddDuration(optionLabels("samplingInterval"), time.Duration(gates.GatesOptions.SamplingInterval)*time.Minute)
+	releaseDefinitionGateOptionsMetric.AddDuration(optionLabels("stabilizationTime"), time.Duration(gates.GatesOptions.StabilizationTime)*time.Minute)
+	releaseDefinitionGateOptionsMetric.AddDuration(optionLabels("minimumSuccessDuration"), time.Duration(gates.GatesOptions.MinimumSuccessDuration)*time.Minute)
+}
+
+func (m *MetricsCollectorRelease) collectReleaseGates(project devopsClient.Project, release devopsClient.Release, environment devopsClient.ReleaseEnvironment, stage string, gates devopsClient.ReleaseGates) {
+	if !gates.IsProcessed() {
+		return
+	}
+
+	releaseGateMetric := m.Collector.GetMetricList("releaseGate")
+	releaseGateEvaluationMetric := m.Collector.GetMetricList("releaseGateEvaluation")
+	releaseGateDurationMetric := m.Collector.GetMetricList("releaseGateDuration")
+
+	ignoredGates := []string{}
+	for _, gate := range gates.IgnoredGates {
+		ignoredGates = append(ignoredGates, gate.Name)
+	}
+
+	releaseGateMetric.AddInfo(prometheus.Labels{
+		"projectID":           project.Id,
+		"releaseID":           int64ToString(release.Id),
+		"releaseDefinitionID": int64ToString(release.Definition.Id),
+		"environmentID":       int64ToString(environment.DefinitionEnvironmentId),
+		"stage":               stage,
+		"status":              gates.Status,
+		"ignoredGates":        strings.Join(ignoredGates, ","),
+	})
+
+	if waitDuration := gates.WaitDuration(); waitDuration != nil {
+		releaseGateDurationMetric.AddDuration(prometheus.Labels{
+			"projectID":           project.Id,
+			"releaseID":           int64ToString(release.Id),
+			"releaseDefinitionID": int64ToString(release.Definition.Id),
+			"environmentID":       int64ToString(environment.DefinitionEnvironmentId),
+			"stage":               stage,
+		}, *waitDuration)
+	}
+
+	// every sampling interval creates a deployment job with one task per gate
+	type evaluationKey struct {
+		GateName string
+		Status   string
+	}
+
+	evaluationCount := map[evaluationKey]int64{}
+	for _, job := range gates.DeploymentJobs {
+		for _, task := range job.Tasks {
+			evaluationCount[evaluationKey{GateName: task.Name, Status: task.Status}]++
+		}
+	}
+
+	for evaluation, count := range evaluationCount {
+		releaseGateEvaluationMetric.Add(prometheus.Labels{
+			"projectID":           project.Id,
+			"releaseID":           int64ToString(release.Id),
+			"releaseDefinitionID": int64ToString(release.Definition.Id),
+			"environmentID":       int64ToString(environment.DefinitionEnvironmentId),
+			"stage":               stage,
+			"gateName":            evaluation.GateName,
+			"status":              evaluation.Status,
+		}, float64(count))
+	}
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/remeh/sizedwaitgroup"
 	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
 	"go.uber.org/zap"
 
 	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
@@ -16,11 +18,15 @@ type MetricsCollectorRepository struct {
 	collector.Processor
 
 	prometheus struct {
-		repository        *prometheus.GaugeVec
-		repositoryStats   *prometheus.GaugeVec
-		repositoryCommits *prometheus.CounterVec
-		repositoryPushes  *prometheus.CounterVec
+		repository             *prometheus.GaugeVec
+		repositoryStats        *prometheus.GaugeVec
+		repositoryCommits      *prometheus.CounterVec
+		repositoryPushes       *prometheus.CounterVec
+		repositoryBranch       *prometheus.GaugeVec
+		repositoryBranchStatus *prometheus.GaugeVec
 	}
+
+	branchPatterns []*regexp.Regexp
 }
 
 func (m *MetricsCollectorRepository) Setup(collector *collector.Collector) {
@@ -75,6 +81,38 @@ func (m *MetricsCollectorRepository) Setup(collector *collector.Collector) {
 		},
 	)
 	m.Collector.RegisterMetricList("repositoryPushes", m.prometheus.repositoryPushes, false)
+
+	m.prometheus.repositoryBranch = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_repository_branch_info",
+			Help: "Azure DevOps repository branch (default branch and branches matching repository.branch.pattern)",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"branchName",
+			"isDefault",
+		},
+	)
+	m.Collector.RegisterMetricList("repositoryBranch", m.prometheus.repositoryBranch, true)
+
+	m.prometheus.repositoryBranchStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_repository_branch_status",
+			Help: "Azure DevOps repository branch status",
+		},
+		[]string{
+			"projectID",
+			"repositoryID",
+			"branchName",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("repositoryBranchStatus", m.prometheus.repositoryBranchStatus, true)
+
+	for _, pattern := range Opts.AzureDevops.RepositoryBranchPattern {
+		m.branchPatterns = append(m.branchPatterns, regexp.MustCompile(pattern))
+	}
 }
 
 func (m *MetricsCollectorRepository) Reset() {}
@@ -149,4 +187,102 @@ func (m *MetricsCollectorRepository) collectRepository(ctx context.Context, logg
 	} else {
 		logger.Error(err)
 	}
+
+	m.collectRepositoryBranches(ctx, logger, callback, project, repository)
+}
+
+func (m *MetricsCollectorRepository) collectRepositoryBranches(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, repository devopsClient.Repository) {
+	// empty repositories don't have any branches
+	if repository.DefaultBranch == "" {
+		return
+	}
+
+	repositoryBranchMetric := m.Collector.GetMetricList("repositoryBranch")
+	repositoryBranchMetric.AddInfo(prometheus.Labels{
+		"projectID":    project.Id,
+		"repositoryID": repository.Id,
+		"branchName":   repository.DefaultBranchName(),
+		"isDefault":    to.BoolString(true),
+	})
+
+	list, err := AzureDevopsClient.ListRepositoryBranchStats(project.Id, repository.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	repositoryStatsMetric := m.Collector.GetMetricList("repositoryStats")
+	repositoryBranchStatusMetric := m.Collector.GetMetricList("repositoryBranchStatus")
+
+	staleTime := time.Now().Add(-Opts.AzureDevops.RepositoryBranchStaleDuration)
+	staleBranchCount := 0
+
+	for _, branch := range list.List {
+		if branch.Commit.Committer.Date.Before(staleTime) {
+			staleBranchCount++
+		}
+
+		if branch.IsBaseVersion {
+			repositoryBranchStatusMetric.AddTime(prometheus.Labels{
+				"projectID":    project.Id,
+				"repositoryID": repository.Id,
+				"branchName":   branch.Name,
+				"type":         "lastCommit",
+			}, branch.Commit.Committer.Date)
+			continue
+		}
+
+		if !m.matchBranchPattern(branch.Name) {
+			continue
+		}
+
+		repositoryBranchMetric.AddInfo(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"branchName":   branch.Name,
+			"isDefault":    to.BoolString(false),
+		})
+
+		repositoryBranchStatusMetric.AddTime(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"branchName":   branch.Name,
+			"type":         "lastCommit",
+		}, branch.Commit.Committer.Date)
+
+		repositoryBranchStatusMetric.Add(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"branchName":   branch.Name,
+			"type":         "aheadCount",
+		}, float64(branch.AheadCount))
+
+		repositoryBranchStatusMetric.Add(prometheus.Labels{
+			"projectID":    project.Id,
+			"repositoryID": repository.Id,
+			"branchName":   branch.Name,
+			"type":         "behindCount",
+		}, float64(branch.BehindCount))
+	}
+
+	repositoryStatsMetric.Add(prometheus.Labels{
+		"projectID":    project.Id,
+		"repositoryID": repository.Id,
+		"type":         "branches",
+	}, float64(len(list.List)))
+
+	repositoryStatsMetric.Add(prometheus.Labels{
+		"projectID":    project.Id,
+		"repositoryID": repository.Id,
+		"type":         "branchesStale",
+	}, float64(staleBranchCount))
+}
+
+func (m *MetricsCollectorRepository) matchBranchPattern(branchName string) bool {
+	for _, pattern := range m.branchPatterns {
+		if pattern.MatchString(branchName) {
+			return true
+		}
+	}
+	return false
 }
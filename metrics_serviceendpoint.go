@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorServiceEndpoint struct {
+	collector.Processor
+
+	prometheus struct {
+		serviceEndpoint           *prometheus.GaugeVec
+		serviceEndpointStatus     *prometheus.GaugeVec
+		serviceEndpointPipeline   *prometheus.GaugeVec
+		serviceEndpointCredential *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorServiceEndpoint) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.serviceEndpoint = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_serviceendpoint_info",
+			Help: "Azure DevOps service endpoint (service connection)",
+		},
+		[]string{
+			"projectID",
+			"serviceEndpointID",
+			"serviceEndpointName",
+			"type",
+			"authorizationScheme",
+			"owner",
+			"createdBy",
+			"url",
+			"isShared",
+			"isReady",
+			"isOutdated",
+		},
+	)
+	m.Collector.RegisterMetricList("serviceEndpoint", m.prometheus.serviceEndpoint, true)
+
+	m.prometheus.serviceEndpointStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_serviceendpoint_status",
+			Help: "Azure DevOps service endpoint status (ready, shared projects, authorized pipelines, last usage)",
+		},
+		[]string{
+			"projectID",
+			"serviceEndpointID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("serviceEndpointStatus", m.prometheus.serviceEndpointStatus, true)
+
+	m.prometheus.serviceEndpointPipeline = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_serviceendpoint_pipeline",
+			Help: "Azure DevOps service endpoint authorized pipelines (authorization time)",
+		},
+		[]string{
+			"projectID",
+			"serviceEndpointID",
+			"pipelineID",
+			"authorizedBy",
+		},
+	)
+	m.Collector.RegisterMetricList("serviceEndpointPipeline", m.prometheus.serviceEndpointPipeline, true)
+
+	if Opts.AzureDevops.ServiceEndpointSecretExpiry {
+		m.prometheus.serviceEndpointCredential = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azure_devops_serviceendpoint_credential",
+				Help: "Azure DevOps service endpoint service principal secret and certificate expiry",
+			},
+			[]string{
+				"projectID",
+				"serviceEndpointID",
+				"servicePrincipalID",
+				"credentialID",
+				"credentialName",
+				"credentialType",
+			},
+		)
+		m.Collector.RegisterMetricList("serviceEndpointCredential", m.prometheus.serviceEndpointCredential, true)
+	}
+}
+
+func (m *MetricsCollectorServiceEndpoint) Reset() {}
+
+func (m *MetricsCollectorServiceEndpoint) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	// shared service endpoints are listed in every project, lookup each application only once
+	applicationCache := map[string]*devopsClient.EntraIdApplication{}
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectServiceEndpoints(ctx, projectLogger, callback, project, applicationCache)
+	}
+}
+
+func (m *MetricsCollectorServiceEndpoint) collectServiceEndpoints(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, applicationCache map[string]*devopsClient.EntraIdApplication) {
+	list, err := AzureDevopsClient.ListServiceEndpoints(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	serviceEndpointMetric := m.Collector.GetMetricList("serviceEndpoint")
+	serviceEndpointStatusMetric := m.Collector.GetMetricList("serviceEndpointStatus")
+	serviceEndpointPipelineMetric := m.Collector.GetMetricList("serviceEndpointPipeline")
+
+	for _, serviceEndpoint := range list.List {
+		serviceEndpointLogger := logger.With(zap.String("serviceEndpoint", serviceEndpoint.Name))
+
+		serviceEndpointMetric.AddInfo(prometheus.Labels{
+			"projectID":           project.Id,
+			"serviceEndpointID":   serviceEndpoint.Id,
+			"serviceEndpointName": serviceEndpoint.Name,
+			"type":                serviceEndpoint.Type,
+			"authorizationScheme": serviceEndpoint.Authorization.Scheme,
+			"owner":               serviceEndpoint.Owner,
+			"createdBy":           serviceEndpoint.CreatedBy.DisplayName,
+			"url":                 serviceEndpoint.Url,
+			"isShared":            to.BoolString(serviceEndpoint.IsShared),
+			"isReady":             to.BoolString(serviceEndpoint.IsReady),
+			"isOutdated":          to.BoolString(serviceEndpoint.IsOutdated),
+		})
+
+		statusLabels := func(statusType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":         project.Id,
+				"serviceEndpointID": serviceEndpoint.Id,
+				"type":              statusType,
+			}
+		}
+
+		serviceEndpointStatusMetric.AddBool(statusLabels("ready"), serviceEndpoint.IsReady)
+		serviceEndpointStatusMetric.AddBool(statusLabels("shared"), serviceEndpoint.IsShared)
+		serviceEndpointStatusMetric.Add(statusLabels("projects"), float64(len(serviceEndpoint.ServiceEndpointProjectReferences)))
+
+		// last usage
+		historyList, err := AzureDevopsClient.ListServiceEndpointExecutionHistory(project.Id, serviceEndpoint.Id, 1)
+		if err == nil {
+			if len(historyList.List) >= 1 {
+				if lastUsed := historyList.List[0].LastUsed(); lastUsed != nil {
+					serviceEndpointStatusMetric.AddTime(statusLabels("lastUsed"), *lastUsed)
+				}
+			}
+		} else {
+			serviceEndpointLogger.Error(err)
+		}
+
+		// authorized pipelines
+		permission, err := AzureDevopsClient.GetPipelinePermission(project.Id, "endpoint", serviceEndpoint.Id)
+		if err == nil {
+			addPipelinePermissionMetrics(serviceEndpointPipelineMetric, serviceEndpointStatusMetric, statusLabels, prometheus.Labels{
+				"projectID":         project.Id,
+				"serviceEndpointID": serviceEndpoint.Id,
+			}, permission)
+		} else {
+			serviceEndpointLogger.Error(err)
+		}
+
+		if Opts.AzureDevops.ServiceEndpointSecretExpiry {
+			if servicePrincipalId := serviceEndpoint.ServicePrincipalId(); servicePrincipalId != "" {
+				application, exists := applicationCache[servicePrincipalId]
+				if !exists {
+					application, err = AzureDevopsClient.GetEntraIdApplication(servicePrincipalId)
+					if err != nil {
+						serviceEndpointLogger.Error(err)
+						continue
+					}
+					applicationCache[servicePrincipalId] = application
+				}
+
+				if application != nil {
+					m.collectServiceEndpointCredentials(project, serviceEndpoint, servicePrincipalId, application)
+				}
+			}
+		}
+	}
+}
+
+// collectServiceEndpointCredentials exports the expiry of secrets and certificates of the application behind the service endpoint
+// (workload identity federation endpoints usually don't have any, federated credentials don't expire)
+func (m *MetricsCollectorServiceEndpoint) collectServiceEndpointCredentials(project devopsClient.Project, serviceEndpoint devopsClient.ServiceEndpoint, servicePrincipalId string, application *devopsClient.EntraIdApplication) {
+	serviceEndpointCredentialMetric := m.Collector.GetMetricList("serviceEndpointCredential")
+
+	credentialList := map[string][]devopsClient.EntraIdApplicationCredential{
+		"secret":      application.PasswordCredentials,
+		"certificate": application.KeyCredentials,
+	}
+
+	for credentialType, credentials := range credentialList {
+		for _, credential := range credentials {
+			if credential.EndDateTime == nil {
+				continue
+			}
+
+			serviceEndpointCredentialMetric.AddTime(prometheus.Labels{
+				"projectID":          project.Id,
+				"serviceEndpointID":  serviceEndpoint.Id,
+				"servicePrincipalID": servicePrincipalId,
+				"credentialID":       credential.KeyId,
+				"credentialName":     credential.DisplayName,
+				"credentialType":     credentialType,
+			}, *credential.EndDateTime)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorTeam struct {
+	collector.Processor
+
+	prometheus struct {
+		team                *prometheus.GaugeVec
+		teamMember          *prometheus.GaugeVec
+		teamAreaPath        *prometheus.GaugeVec
+		teamIteration       *prometheus.GaugeVec
+		teamIterationStatus *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorTeam) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.team = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_team_info",
+			Help: "Azure DevOps team",
+		},
+		[]string{
+			"projectID",
+			"teamID",
+			"teamName",
+		},
+	)
+	m.Collector.RegisterMetricList("team", m.prometheus.team, true)
+
+	m.prometheus.teamMember = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_team_member",
+			Help: "Azure DevOps team member",
+		},
+		[]string{
+			"projectID",
+			"teamID",
+			"userID",
+			"userName",
+			"isTeamAdmin",
+		},
+	)
+	m.Collector.RegisterMetricList("teamMember", m.prometheus.teamMember, true)
+
+	m.prometheus.teamAreaPath = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_team_areapath",
+			Help: "Azure DevOps team area path (team field value)",
+		},
+		[]string{
+			"projectID",
+			"teamID",
+			"areaPath",
+			"includeChildren",
+			"isDefault",
+		},
+	)
+	m.Collector.RegisterMetricList("teamAreaPath", m.prometheus.teamAreaPath, true)
+
+	m.prometheus.teamIteration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_team_iteration",
+			Help: "Azure DevOps team iteration",
+		},
+		[]string{
+			"projectID",
+			"teamID",
+			"iterationID",
+			"iterationName",
+			"iterationPath",
+			"timeFrame",
+		},
+	)
+	m.Collector.RegisterMetricList("teamIteration", m.prometheus.teamIteration, true)
+
+	m.prometheus.teamIterationStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_team_iteration_status",
+			Help: "Azure DevOps team iteration status (start and finish date)",
+		},
+		[]string{
+			"projectID",
+			"teamID",
+			"iterationID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("teamIterationStatus", m.prometheus.teamIterationStatus, true)
+}
+
+func (m *MetricsCollectorTeam) Reset() {}
+
+func (m *MetricsCollectorTeam) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectTeams(ctx, projectLogger, callback, project)
+	}
+}
+
+func (m *MetricsCollectorTeam) collectTeams(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListTeams(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	teamMetric := m.Collector.GetMetricList("team")
+
+	for _, team := range list.List {
+		teamMetric.AddInfo(prometheus.Labels{
+			"projectID": project.Id,
+			"teamID":    team.Id,
+			"teamName":  team.Name,
+		})
+
+		teamLogger := logger.With(zap.String("team", team.Name))
+		m.collectTeamMembers(ctx, teamLogger, callback, project, team)
+		m.collectTeamSettings(ctx, teamLogger, callback, project, team)
+	}
+}
+
+func (m *MetricsCollectorTeam) collectTeamMembers(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, team devopsClient.Team) {
+	list, err := AzureDevopsClient.ListTeamMembers(project.Id, team.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	teamMemberMetric := m.Collector.GetMetricList("teamMember")
+
+	for _, member := range list.List {
+		teamMemberMetric.AddInfo(prometheus.Labels{
+			"projectID":   project.Id,
+			"teamID":      team.Id,
+			"userID":      member.Identity.Id,
+			"userName":    member.Identity.UniqueName,
+			"isTeamAdmin": to.BoolString(member.IsTeamAdmin),
+		})
+	}
+}
+
+func (m *MetricsCollectorTeam) collectTeamSettings(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, team devopsClient.Team) {
+	teamAreaPathMetric := m.Collector.GetMetricList("teamAreaPath")
+	teamIterationMetric := m.Collector.GetMetricList("teamIteration")
+	teamIterationStatusMetric := m.Collector.GetMetricList("teamIterationStatus")
+
+	fieldValues, err := AzureDevopsClient.GetTeamFieldValues(project.Id, team.Id)
+	if err != nil {
+		logger.Error(err)
+	} else {
+		for _, value := range fieldValues.Values {
+			teamAreaPathMetric.AddInfo(prometheus.Labels{
+				"projectID":       project.Id,
+				"teamID":          team.Id,
+				"areaPath":        value.Value,
+				"includeChildren": to.BoolString(value.IncludeChildren),
+				"isDefault":       to.BoolString(value.Value == fieldValues.DefaultValue),
+			})
+		}
+	}
+
+	iterationList, err := AzureDevopsClient.ListTeamIterations(project.Id, team.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, iteration := range iterationList.List {
+		teamIterationMetric.AddInfo(prometheus.Labels{
+			"projectID":     project.Id,
+			"teamID":        team.Id,
+			"iterationID":   iteration.Id,
+			"iterationName": iteration.Name,
+			"iterationPath": iteration.Path,
+			"timeFrame":     iteration.Attributes.TimeFrame,
+		})
+
+		if iteration.Attributes.StartDate != nil {
+			teamIterationStatusMetric.AddTime(prometheus.Labels{
+				"projectID":   project.Id,
+				"teamID":      team.Id,
+				"iterationID": iteration.Id,
+				"type":        "startDate",
+			}, *iteration.Attributes.StartDate)
+		}
+
+		if iteration.Attributes.FinishDate != nil {
+			teamIterationStatusMetric.AddTime(prometheus.Labels{
+				"projectID":   project.Id,
+				"teamID":      team.Id,
+				"iterationID": iteration.Id,
+				"type":        "finishDate",
+			}, *iteration.Attributes.FinishDate)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorTestPlan struct {
+	collector.Processor
+
+	prometheus struct {
+		testPlan        *prometheus.GaugeVec
+		testPlanStatus  *prometheus.GaugeVec
+		testSuite       *prometheus.GaugeVec
+		testPlanPoints  *prometheus.GaugeVec
+		testPlanTester  *prometheus.GaugeVec
+		testPlanLastRun *prometheus.GaugeVec
+	}
+}
+
+// testPointKey is the aggregation key of test points per configuration and outcome
+type testPointKey struct {
+	Configuration string
+	Outcome       string
+}
+
+// testTesterKey is the aggregation key of test points per tester and outcome
+type testTesterKey struct {
+	Tester  string
+	Outcome string
+}
+
+func (m *MetricsCollectorTestPlan) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.testPlan = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_info",
+			Help: "Azure DevOps test plan",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"testPlanName",
+			"state",
+			"areaPath",
+			"iteration",
+			"owner",
+		},
+	)
+	m.Collector.RegisterMetricList("testPlan", m.prometheus.testPlan, true)
+
+	m.prometheus.testPlanStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_status",
+			Help: "Azure DevOps test plan status (suites, points, start, end and updated time)",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("testPlanStatus", m.prometheus.testPlanStatus, true)
+
+	m.prometheus.testSuite = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_suite_info",
+			Help: "Azure DevOps test plan suite",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"testSuiteID",
+			"testSuiteName",
+			"suiteType",
+			"parentSuiteID",
+		},
+	)
+	m.Collector.RegisterMetricList("testSuite", m.prometheus.testSuite, true)
+
+	m.prometheus.testPlanPoints = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_points",
+			Help: "Azure DevOps test plan number of test points per configuration and outcome",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"configuration",
+			"outcome",
+		},
+	)
+	m.Collector.RegisterMetricList("testPlanPoints", m.prometheus.testPlanPoints, true)
+
+	m.prometheus.testPlanTester = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_tester",
+			Help: "Azure DevOps test plan number of test points per assigned tester and outcome",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"tester",
+			"outcome",
+		},
+	)
+	m.Collector.RegisterMetricList("testPlanTester", m.prometheus.testPlanTester, true)
+
+	m.prometheus.testPlanLastRun = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_testplan_lastrun",
+			Help: "Azure DevOps test plan last test run per configuration (completion time)",
+		},
+		[]string{
+			"projectID",
+			"testPlanID",
+			"configuration",
+		},
+	)
+	m.Collector.RegisterMetricList("testPlanLastRun", m.prometheus.testPlanLastRun, true)
+}
+
+func (m *MetricsCollectorTestPlan) Reset() {}
+
+func (m *MetricsCollectorTestPlan) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	for _, project := range AzureDevopsServiceDiscovery.ProjectList() {
+		projectLogger := logger.With(zap.String("project", project.Name))
+		m.collectTestPlans(ctx, projectLogger, callback, project)
+	}
+}
+
+func (m *MetricsCollectorTestPlan) collectTestPlans(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project) {
+	list, err := AzureDevopsClient.ListTestPlans(project.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	testPlanMetric := m.Collector.GetMetricList("testPlan")
+	testPlanStatusMetric := m.Collector.GetMetricList("testPlanStatus")
+
+	for _, testPlan := range list.List {
+		testPlanId := int64ToString(testPlan.Id)
+
+		testPlanMetric.AddInfo(prometheus.Labels{
+			"projectID":    project.Id,
+			"testPlanID":   testPlanId,
+			"testPlanName": testPlan.Name,
+			"state":        testPlan.State,
+			"areaPath":     testPlan.AreaPath,
+			"iteration":    testPlan.Iteration,
+			"owner":        testPlan.Owner.DisplayName,
+		})
+
+		statusLabels := func(statusType string) prometheus.Labels {
+			return prometheus.Labels{
+				"projectID":  project.Id,
+				"testPlanID": testPlanId,
+				"type":       statusType,
+			}
+		}
+
+		if testPlan.StartDate != nil {
+			testPlanStatusMetric.AddTime(statusLabels("start"), *testPlan.StartDate)
+		}
+
+		if testPlan.EndDate != nil {
+			testPlanStatusMetric.AddTime(statusLabels("end"), *testPlan.EndDate)
+		}
+
+		if testPlan.UpdatedDate != nil {
+			testPlanStatusMetric.AddTime(statusLabels("updated"), *testPlan.UpdatedDate)
+		}
+
+		suiteCount, pointCount := m.collectTestSuites(ctx, logger.With(zap.String("testPlan", testPlan.Name)), callback, project, testPlan)
+		testPlanStatusMetric.Add(statusLabels("suites"), float64(suiteCount))
+		testPlanStatusMetric.Add(statusLabels("points"), float64(pointCount))
+	}
+}
+
+// collectTestSuites exports the suites and point outcomes of a test plan, returns the number of suites and points
+func (m *MetricsCollectorTestPlan) collectTestSuites(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), project devopsClient.Project, testPlan devopsClient.TestPlan) (suiteCount int, pointCount int) {
+	list, err := AzureDevopsClient.ListTestSuites(project.Id, testPlan.Id)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	testSuiteMetric := m.Collector.GetMetricList("testSuite")
+	testPlanPointsMetric := m.Collector.GetMetricList("testPlanPoints")
+	testPlanTesterMetric := m.Collector.GetMetricList("testPlanTester")
+	testPlanLastRunMetric := m.Collector.GetMetricList("testPlanLastRun")
+
+	testPlanId := int64ToString(testPlan.Id)
+
+	pointStats := map[testPointKey]int{}
+	testerStats := map[testTesterKey]int{}
+	lastRun := map[string]time.Time{}
+
+	for _, testSuite := range list.List {
+		suiteCount++
+
+		parentSuiteId := ""
+		if testSuite.ParentSuite != nil {
+			parentSuiteId = int64ToString(testSuite.ParentSuite.Id)
+		}
+
+		testSuiteMetric.AddInfo(prometheus.Labels{
+			"projectID":     project.Id,
+			"testPlanID":    testPlanId,
+			"testSuiteID":   int64ToString(testSuite.Id),
+			"testSuiteName": testSuite.Name,
+			"suiteType":     testSuite.SuiteType,
+			"parentSuiteID": parentSuiteId,
+		})
+
+		pointList, err := AzureDevopsClient.ListTestPoints(project.Id, testPlan.Id, testSuite.Id)
+		if err != nil {
+			logger.With(zap.String("testSuite", testSuite.Name)).Error(err)
+			continue
+		}
+
+		for _, testPoint := range pointList.List {
+			if !testPoint.IsActive {
+				continue
+			}
+			pointCount++
+
+			outcome := testPoint.Outcome()
+			pointStats[testPointKey{
+				Configuration: testPoint.Configuration.Name,
+				Outcome:       outcome,
+			}]++
+
+			testerStats[testTesterKey{
+				Tester:  testPoint.Tester.DisplayName,
+				Outcome: outcome,
+			}]++
+
+			if completed := testPoint.LastRun(); completed != nil {
+				if val, exists := lastRun[testPoint.Configuration.Name]; !exists || completed.After(val) {
+					lastRun[testPoint.Configuration.Name] = *completed
+				}
+			}
+		}
+	}
+
+	for key, count := range pointStats {
+		testPlanPointsMetric.Add(prometheus.Labels{
+			"projectID":     project.Id,
+			"testPlanID":    testPlanId,
+			"configuration": key.Configuration,
+			"outcome":       key.Outcome,
+		}, float64(count))
+	}
+
+	for key, count := range testerStats {
+		testPlanTesterMetric.Add(prometheus.Labels{
+			"projectID":  project.Id,
+			"testPlanID": testPlanId,
+			"tester":     key.Tester,
+			"outcome":    key.Outcome,
+		}, float64(count))
+	}
+
+	for configuration, completed := range lastRun {
+		testPlanLastRunMetric.AddTime(prometheus.Labels{
+			"projectID":     project.Id,
+			"testPlanID":    testPlanId,
+			"configuration": configuration,
+		}, completed)
+	}
+
+	return
+}
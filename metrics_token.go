@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	devopsClient "github.com/webdevops/azure-devops-exporter/azure-devops-client"
+)
+
+type MetricsCollectorToken struct {
+	collector.Processor
+
+	prometheus struct {
+		personalAccessToken *prometheus.GaugeVec
+	}
+}
+
+func (m *MetricsCollectorToken) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.personalAccessToken = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_personalaccesstoken_expiry",
+			Help: "Azure DevOps personal access token expiry (valid until)",
+		},
+		[]string{
+			"userID",
+			"userName",
+			"tokenID",
+			"tokenName",
+			"scope",
+			"self",
+		},
+	)
+	m.Collector.RegisterMetricList("personalAccessToken", m.prometheus.personalAccessToken, true)
+}
+
+func (m *MetricsCollectorToken) Reset() {}
+
+func (m *MetricsCollectorToken) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	// identity of the exporter (tokens of this identity are marked as self)
+	connectionData, err := AzureDevopsClient.GetConnectionData()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	selfDescriptor := connectionData.AuthenticatedUser.SubjectDescriptor
+
+	userList, err := AzureDevopsClient.ListGraphUsers()
+	if err != nil {
+		// listing organization users might not be permitted, fall back to the exporter identity
+		logger.Warn(err)
+		userList.List = []devopsClient.GraphUser{
+			{
+				Descriptor:  selfDescriptor,
+				DisplayName: connectionData.AuthenticatedUser.ProviderDisplayName,
+			},
+		}
+	}
+
+	for _, user := range userList.List {
+		userLogger := logger.With(zap.String("user", user.PrincipalName))
+		m.collectPersonalAccessTokens(ctx, userLogger, callback, user, user.Descriptor == selfDescriptor)
+	}
+}
+
+func (m *MetricsCollectorToken) collectPersonalAccessTokens(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), user devopsClient.GraphUser, self bool) {
+	list, err := AzureDevopsClient.ListPersonalAccessTokens(user.Descriptor)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	personalAccessTokenMetric := m.Collector.GetMetricList("personalAccessToken")
+
+	for _, token := range list.List {
+		if !token.IsValid {
+			continue
+		}
+
+		personalAccessTokenMetric.AddTime(prometheus.Labels{
+			"userID":    user.Descriptor,
+			"userName":  user.DisplayName,
+			"tokenID":   token.AuthorizationId,
+			"tokenName": token.DisplayName,
+			"scope":     token.Scope,
+			"self":      to.BoolString(self),
+		}, token.ValidTo)
+	}
+}
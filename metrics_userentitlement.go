@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/webdevops/go-common/prometheus/collector"
+	"go.uber.org/zap"
+)
+
+type MetricsCollectorUserEntitlement struct {
+	collector.Processor
+
+	prometheus struct {
+		userAccessLevel     *prometheus.GaugeVec
+		userLastAccess      *prometheus.GaugeVec
+		userInactive        *prometheus.GaugeVec
+		userGroupRule       *prometheus.GaugeVec
+		userGroupRuleStatus *prometheus.GaugeVec
+	}
+}
+
+// userAccessLevelKey is the aggregation key of users per access level
+type userAccessLevelKey struct {
+	AccessLevel      string
+	LicenseType      string
+	LicensingSource  string
+	AssignmentSource string
+	Status           string
+}
+
+// userLastAccessKey is the aggregation key of users per access level and last access period
+type userLastAccessKey struct {
+	AccessLevel string
+	Period      string
+}
+
+func (m *MetricsCollectorUserEntitlement) Setup(collector *collector.Collector) {
+	m.Processor.Setup(collector)
+
+	m.prometheus.userAccessLevel = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_user_accesslevel",
+			Help: "Azure DevOps number of users per access level (license)",
+		},
+		[]string{
+			"accessLevel",
+			"licenseType",
+			"licensingSource",
+			"assignmentSource",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("userAccessLevel", m.prometheus.userAccessLevel, true)
+
+	m.prometheus.userLastAccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_user_lastaccess",
+			Help: "Azure DevOps number of users per access level and last access period (7d, 30d, 90d, older, never)",
+		},
+		[]string{
+			"accessLevel",
+			"period",
+		},
+	)
+	m.Collector.RegisterMetricList("userLastAccess", m.prometheus.userLastAccess, true)
+
+	m.prometheus.userInactive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_user_inactive",
+			Help: "Azure DevOps inactive users with paid access level (last access time, 0 if never accessed)",
+		},
+		[]string{
+			"userID",
+			"userName",
+			"accessLevel",
+			"licenseType",
+		},
+	)
+	m.Collector.RegisterMetricList("userInactive", m.prometheus.userInactive, true)
+
+	m.prometheus.userGroupRule = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_user_grouprule_info",
+			Help: "Azure DevOps group rule (access level assignment of group members)",
+		},
+		[]string{
+			"groupRuleID",
+			"groupName",
+			"accessLevel",
+			"licenseType",
+			"status",
+		},
+	)
+	m.Collector.RegisterMetricList("userGroupRule", m.prometheus.userGroupRule, true)
+
+	m.prometheus.userGroupRuleStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_devops_user_grouprule_status",
+			Help: "Azure DevOps group rule status (assigned users, last execution time)",
+		},
+		[]string{
+			"groupRuleID",
+			"type",
+		},
+	)
+	m.Collector.RegisterMetricList("userGroupRuleStatus", m.prometheus.userGroupRuleStatus, true)
+}
+
+func (m *MetricsCollectorUserEntitlement) Reset() {}
+
+func (m *MetricsCollectorUserEntitlement) Collect(callback chan<- func()) {
+	ctx := m.Context()
+	logger := m.Logger()
+
+	groupRuleUsers := m.collectUserEntitlements(ctx, logger, callback)
+	m.collectGroupEntitlements(ctx, logger, callback, groupRuleUsers)
+}
+
+// collectUserEntitlements exports the access levels of all users, returns the number of users per group rule (group descriptor)
+func (m *MetricsCollectorUserEntitlement) collectUserEntitlements(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func()) map[string]int {
+	groupRuleUsers := map[string]int{}
+
+	list, err := AzureDevopsClient.ListUserEntitlements()
+	if err != nil {
+		logger.Error(err)
+		return groupRuleUsers
+	}
+
+	userInactiveMetric := m.Collector.GetMetricList("userInactive")
+
+	now := time.Now()
+	accessLevelStats := map[userAccessLevelKey]int{}
+	lastAccessStats := map[userLastAccessKey]int{}
+
+	for _, user := range list.List {
+		accessLevel := user.AccessLevel.LicenseDisplayName
+
+		accessLevelStats[userAccessLevelKey{
+			AccessLevel:      accessLevel,
+			LicenseType:      user.AccessLevel.LicenseType(),
+			LicensingSource:  user.AccessLevel.LicensingSource,
+			AssignmentSource: user.AccessLevel.AssignmentSource,
+			Status:           user.AccessLevel.Status,
+		}]++
+
+		lastAccessed := user.LastAccessed()
+		lastAccessStats[userLastAccessKey{
+			AccessLevel: accessLevel,
+			Period:      userLastAccessPeriod(now, lastAccessed),
+		}]++
+
+		// inactive users with paid licenses
+		if user.AccessLevel.IsPaid() && (lastAccessed == nil || now.Sub(*lastAccessed) > Opts.AzureDevops.UserInactiveDuration) {
+			userLabels := prometheus.Labels{
+				"userID":      user.Id,
+				"userName":    user.User.PrincipalName,
+				"accessLevel": accessLevel,
+				"licenseType": user.AccessLevel.LicenseType(),
+			}
+
+			if lastAccessed != nil {
+				userInactiveMetric.AddTime(userLabels, *lastAccessed)
+			} else {
+				userInactiveMetric.Add(userLabels, 0)
+			}
+		}
+
+		for _, groupAssignment := range user.GroupAssignments {
+			groupRuleUsers[groupAssignment.Group.Descriptor]++
+		}
+	}
+
+	userAccessLevelMetric := m.Collector.GetMetricList("userAccessLevel")
+	for key, count := range accessLevelStats {
+		userAccessLevelMetric.Add(prometheus.Labels{
+			"accessLevel":      key.AccessLevel,
+			"licenseType":      key.LicenseType,
+			"licensingSource":  key.LicensingSource,
+			"assignmentSource": key.AssignmentSource,
+			"status":           key.Status,
+		}, float64(count))
+	}
+
+	userLastAccessMetric := m.Collector.GetMetricList("userLastAccess")
+	for key, count := range lastAccessStats {
+		userLastAccessMetric.Add(prometheus.Labels{
+			"accessLevel": key.AccessLevel,
+			"period":      key.Period,
+		}, float64(count))
+	}
+
+	return groupRuleUsers
+}
+
+func (m *MetricsCollectorUserEntitlement) collectGroupEntitlements(ctx context.Context, logger *zap.SugaredLogger, callback chan<- func(), groupRuleUsers map[string]int) {
+	list, err := AzureDevopsClient.ListGroupEntitlements()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	userGroupRuleMetric := m.Collector.GetMetricList("userGroupRule")
+	userGroupRuleStatusMetric := m.Collector.GetMetricList("userGroupRuleStatus")
+
+	for _, groupRule := range list.List {
+		userGroupRuleMetric.AddInfo(prometheus.Labels{
+			"groupRuleID": groupRule.Id,
+			"groupName":   groupRule.Group.DisplayName,
+			"accessLevel": groupRule.LicenseRule.LicenseDisplayName,
+			"licenseType": groupRule.LicenseRule.LicenseType(),
+			"status":      groupRule.Status,
+		})
+
+		userGroupRuleStatusMetric.Add(prometheus.Labels{
+			"groupRuleID": groupRule.Id,
+			"type":        "users",
+		}, float64(groupRuleUsers[groupRule.Group.Descriptor]))
+
+		if groupRule.LastExecuted != nil {
+			userGroupRuleStatusMetric.AddTime(prometheus.Labels{
+				"groupRuleID": groupRule.Id,
+				"type":        "lastExecuted",
+			}, *groupRule.LastExecuted)
+		}
+	}
+}
+
+// userLastAccessPeriod returns the last access period bucket of an user
+func userLastAccessPeriod(now time.Time, lastAccessed *time.Time) string {
+	if lastAccessed == nil {
+		return "never"
+	}
+
+	return ageBucket(now.Sub(*lastAccessed))
+}
@@ -1,10 +1,45 @@
 package main
 
 import (
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
+var (
+	issueMessageNormalizeReplacer = []struct {
+		regexp      *regexp.Regexp
+		replacement string
+	}{
+		{regexp.MustCompile(`^##\[[a-z]+\]`), ""},
+		{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s'"]+`), "<url>"},
+		{regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<guid>"},
+		{regexp.MustCompile(`(?:[a-zA-Z]:)?(?:[\\/][^\s\\/'":]+){2,}[\\/]?`), "<path>"},
+		{regexp.MustCompile(`(?i)\b[0-9a-f]{7,}\b`), "<hash>"},
+		{regexp.MustCompile(`[0-9]+(?:\.[0-9]+)*`), "<n>"},
+		{regexp.MustCompile(`\s+`), " "},
+	}
+)
+
+// normalizeIssueMessage creates a signature of error messages by replacing variable parts (urls, ids, paths, numbers)
+func normalizeIssueMessage(message string) string {
+	// label values must be valid UTF-8
+	message = strings.ToValidUTF8(message, "")
+
+	for _, replacer := range issueMessageNormalizeReplacer {
+		message = replacer.regexp.ReplaceAllString(message, replacer.replacement)
+	}
+
+	// truncate on rune boundary
+	message = strings.TrimSpace(message)
+	if runes := []rune(message); len(runes) > 200 {
+		message = string(runes[:200])
+	}
+
+	return message
+}
+
 func int64ToString(v int64) string {
 	return strconv.FormatInt(v, 10)
 }
@@ -30,3 +65,28 @@ func arrayIntContains(s []int64, e int64) bool {
 func timeToFloat64(v time.Time) float64 {
 	return float64(v.Unix())
 }
+
+// billingPeriod returns the billing period (month of period start, eg. 2024-01) of a time,
+// periods start at the billing day of each month
+func billingPeriod(t time.Time, billingDay int) string {
+	t = t.UTC()
+	periodStart := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if t.Day() < billingDay {
+		periodStart = periodStart.AddDate(0, -1, 0)
+	}
+	return periodStart.Format("2006-01")
+}
+
+// ageBucket returns the age bucket (7d, 30d, 90d, older) of a duration
+func ageBucket(age time.Duration) string {
+	switch {
+	case age <= 7*24*time.Hour:
+		return "7d"
+	case age <= 30*24*time.Hour:
+		return "30d"
+	case age <= 90*24*time.Hour:
+		return "90d"
+	default:
+		return "older"
+	}
+}
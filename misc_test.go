@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNormalizeIssueMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "empty",
+			message:  "",
+			expected: "",
+		},
+		{
+			name:     "log command prefix",
+			message:  "##[error]Process completed with exit code 1.",
+			expected: "Process completed with exit code <n>.",
+		},
+		{
+			name:     "url",
+			message:  "Failed to download https://example.com/files/agent.zip",
+			expected: "Failed to download <url>",
+		},
+		{
+			name:     "guid",
+			message:  "Job 3F2504E0-4F89-11D3-9A0C-0305E82C3301 failed",
+			expected: "Job <guid> failed",
+		},
+		{
+			name:     "unix path",
+			message:  "File /home/vsts/work/1/s/main.go not found",
+			expected: "File <path> not found",
+		},
+		{
+			name:     "windows path",
+			message:  `File D:\a\1\s\main.go not found`,
+			expected: "File <path> not found",
+		},
+		{
+			name:     "commit hash",
+			message:  "Checkout of commit 9fceb02d0ae598e95dc970b74767f19372d61af8 failed",
+			expected: "Checkout of commit <hash> failed",
+		},
+		{
+			name:     "whitespace",
+			message:  "  multiple\t\tspaces\n and lines  ",
+			expected: "multiple spaces and lines",
+		},
+		{
+			name:     "invalid utf-8",
+			message:  "broken \xff\xfe message",
+			expected: "broken message",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := normalizeIssueMessage(test.message); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNormalizeIssueMessageTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{
+			name:    "ascii",
+			message: strings.Repeat("x", 300),
+		},
+		{
+			name:    "multibyte",
+			message: strings.Repeat("ü", 300),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := normalizeIssueMessage(test.message)
+			if length := utf8.RuneCountInString(actual); length != 200 {
+				t.Errorf("expected 200 runes, got %v", length)
+			}
+			if !utf8.ValidString(actual) {
+				t.Errorf("expected valid utf-8, got %q", actual)
+			}
+		})
+	}
+}
+
+func TestBillingPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		time       time.Time
+		billingDay int
+		expected   string
+	}{
+		{
+			name:       "first day of month",
+			time:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			billingDay: 1,
+			expected:   "2024-05",
+		},
+		{
+			name:       "end of month",
+			time:       time.Date(2024, 5, 31, 23, 59, 59, 0, time.UTC),
+			billingDay: 1,
+			expected:   "2024-05",
+		},
+		{
+			name:       "before billing day",
+			time:       time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC),
+			billingDay: 15,
+			expected:   "2024-04",
+		},
+		{
+			name:       "on billing day",
+			time:       time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+			billingDay: 15,
+			expected:   "2024-05",
+		},
+		{
+			name:       "before billing day in january",
+			time:       time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			billingDay: 15,
+			expected:   "2023-12",
+		},
+		{
+			name:       "non utc timezone",
+			time:       time.Date(2024, 6, 1, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			billingDay: 1,
+			expected:   "2024-05",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := billingPeriod(test.time, test.billingDay); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAgeBucket(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected string
+	}{
+		{name: "new", age: 0, expected: "7d"},
+		{name: "7 days", age: 7 * day, expected: "7d"},
+		{name: "8 days", age: 8 * day, expected: "30d"},
+		{name: "30 days", age: 30 * day, expected: "30d"},
+		{name: "31 days", age: 31 * day, expected: "90d"},
+		{name: "90 days", age: 90 * day, expected: "90d"},
+		{name: "91 days", age: 91 * day, expected: "older"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := ageBucket(test.age); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}